@@ -4,6 +4,7 @@ go 1.14
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/envoyproxy/go-control-plane v0.9.5
 	github.com/go-redis/redis v6.15.7+incompatible
 	github.com/golang/protobuf v1.3.5
 	github.com/onsi/ginkgo v1.12.0 // indirect
@@ -11,5 +12,7 @@ require (
 	github.com/spf13/viper v1.6.2
 	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
 	golang.org/x/sync v0.0.0-20190423024810-112230192c58
+	google.golang.org/genproto v0.0.0-20190819201941-24fa4b261c55
 	google.golang.org/grpc v1.28.0
+	gopkg.in/yaml.v2 v2.2.4
 )
@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/joshturge-io/auth/pkg/policy"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// Authorize will validate a jwt and evaluate the policy against its claims to decide if the
+// action can be performed on the resource. Invalid, expired and revoked jwts are denied
+func (s *Service) Authorize(ctx context.Context, tokenStr, action,
+	resource string) (*policy.Decision, error) {
+	jw, err := token.NewJWFromExisting(s.keyring, s.opt.JWTOptions, tokenStr)
+	if err != nil {
+		if errors.Is(err, token.ErrJWExpired) {
+			return &policy.Decision{Reason: "token has expired"}, nil
+		}
+		if errors.Is(err, token.ErrJWInvalid) {
+			return &policy.Decision{Reason: "token is invalid"}, nil
+		}
+		return nil, err
+	}
+
+	if jw.IsExpired() {
+		return &policy.Decision{Reason: "token has expired"}, nil
+	}
+
+	s.repo.WithContext(ctx)
+	revoked, err := s.isRevoked(jw)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return &policy.Decision{Reason: "token has been revoked"}, nil
+	}
+
+	if s.opt.Policy == nil {
+		return &policy.Decision{Reason: "no policy has been loaded"}, nil
+	}
+
+	return s.opt.Policy.Evaluate(&policy.Request{
+		Subject:  jw.Username(),
+		Grants:   jw.Grants(),
+		Action:   action,
+		Resource: resource,
+	}), nil
+}
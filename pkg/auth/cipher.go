@@ -61,8 +61,7 @@ func (c *Challenger) chooseRandomKeyIndex() (int, error) {
 func (c *Challenger) encrypt(data []byte, keyIndex int) ([]byte, error) {
 	block, err := aes.NewCipher(c.cipherKeys[keyIndex])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create new cipher for key: %x: %w",
-			c.cipherKeys[keyIndex], err)
+		return nil, fmt.Errorf("failed to create new cipher for key: %d: %w", keyIndex, err)
 	}
 
 	gcm, err := cipher.NewGCM(block)
@@ -81,8 +80,7 @@ func (c *Challenger) encrypt(data []byte, keyIndex int) ([]byte, error) {
 func (c *Challenger) decrypt(data []byte, keyIndex int) ([]byte, error) {
 	block, err := aes.NewCipher(c.cipherKeys[keyIndex])
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode cipher with key: %x: %w",
-			c.cipherKeys[keyIndex], err)
+		return nil, fmt.Errorf("failed to decode cipher with key: %d: %w", keyIndex, err)
 	}
 
 	gcm, err := cipher.NewGCM(block)
@@ -97,7 +95,12 @@ func (c *Challenger) decrypt(data []byte, keyIndex int) ([]byte, error) {
 
 	nounce, data := data[:nounceSize], data[nounceSize:]
 
-	return gcm.Open(nil, nounce, data, nil)
+	plain, err := gcm.Open(nil, nounce, data, nil)
+	if err != nil {
+		return nil, ErrMessAuthFailed
+	}
+
+	return plain, nil
 }
 
 // Validate a challenge by checking if we can recreate the cipher from the salt and password
@@ -141,7 +144,7 @@ func (c *Challenger) Generate(password string) (salt string, cipher string, err
 
 	keyIndex, err := c.chooseRandomKeyIndex()
 	if err != nil {
-		return "", "", nil
+		return "", "", err
 	}
 
 	ciph, err := c.encrypt(generateChallengeHash(randBytes, []byte(password)), keyIndex)
@@ -151,3 +154,41 @@ func (c *Challenger) Generate(password string) (salt string, cipher string, err
 
 	return hex.EncodeToString(randBytes), hex.EncodeToString(ciph), nil
 }
+
+// Encrypt data at rest with a random key, returns the hex encoded cipher
+func (c *Challenger) Encrypt(data []byte) (string, error) {
+	keyIndex, err := c.chooseRandomKeyIndex()
+	if err != nil {
+		return "", err
+	}
+
+	ciph, err := c.encrypt(data, keyIndex)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	return hex.EncodeToString(ciph), nil
+}
+
+// Decrypt a hex encoded cipher created by Encrypt, every key is tried until one authenticates
+// the message. Returns ErrMessAuthFailed if none of the keys do
+func (c *Challenger) Decrypt(cipherStr string) ([]byte, error) {
+	ciph, err := hex.DecodeString(cipherStr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode cipher string: %w", err)
+	}
+
+	for i := range c.cipherKeys {
+		plain, err := c.decrypt(ciph, i)
+		if err != nil {
+			if errors.Is(err, ErrMessAuthFailed) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to decrypt data: %w", err)
+		}
+
+		return plain, nil
+	}
+
+	return nil, ErrMessAuthFailed
+}
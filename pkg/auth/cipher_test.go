@@ -2,6 +2,7 @@ package auth_test
 
 import (
 	"encoding/hex"
+	"errors"
 	"testing"
 
 	"github.com/joshturge-io/auth/pkg/auth"
@@ -59,3 +60,26 @@ func TestValidate(t *testing.T) {
 		t.Error("cipher is not valid")
 	}
 }
+
+func TestEncryptDecrypt(t *testing.T) {
+	ciph, err := chall.Encrypt([]byte("secret"))
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	plain, err := chall.Decrypt(ciph)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if string(plain) != "secret" {
+		t.Errorf("decrypted data does not match wanted: secret got: %s", plain)
+	}
+
+	other := auth.NewChallenger(16, [][]byte{[]byte("Zb8fGq1kP0sXy7Lm3Nc2Vd9Rt4Wh6Ja5")})
+	if _, err = other.Decrypt(ciph); !errors.Is(err, auth.ErrMessAuthFailed) {
+		t.Errorf("expected ErrMessAuthFailed got: %v", err)
+	}
+}
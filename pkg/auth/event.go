@@ -0,0 +1,39 @@
+package auth
+
+import "time"
+
+// EventType describes what caused a security event
+type EventType string
+
+const (
+	// EventRefreshReuse is emitted when a refresh token that has already been rotated is
+	// presented again, the whole session is revoked when this happens
+	EventRefreshReuse EventType = "refresh_token_reuse"
+)
+
+// SecurityEvent is something that happened to a user that may point to an attack
+type SecurityEvent struct {
+	Type      EventType
+	UserId    string
+	SessionId string
+	Device    Device
+	Time      time.Time
+}
+
+// EventHandler is called with every security event the service emits
+type EventHandler func(*SecurityEvent)
+
+// emit a security event to the event handler if one has been set
+func (s *Service) emit(typ EventType, userId, sessionId string, dev Device) {
+	if s.opt.EventHandler == nil {
+		return
+	}
+
+	s.opt.EventHandler(&SecurityEvent{
+		Type:      typ,
+		UserId:    userId,
+		SessionId: sessionId,
+		Device:    dev,
+		Time:      time.Now(),
+	})
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	ErrAccountLocked   = errors.New("account is locked")
+	ErrTooManyAttempts = errors.New("too many failed attempts")
+)
+
+// EventAccountLocked is emitted when a users account is locked after too many failed attempts
+const EventAccountLocked EventType = "account_locked"
+
+// RetryError is returned with ErrAccountLocked or ErrTooManyAttempts, saying when another
+// attempt is allowed
+type RetryError struct {
+	Err     error
+	RetryAt time.Time
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("retry after: %s: %s", e.RetryAt.Format(time.RFC3339), e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// LockoutPolicy holds the thresholds after which failed challenges slow down or lock out
+// further attempts. Failed attempts aren't tracked when the window is zero
+type LockoutPolicy struct {
+	// Sliding window failed attempts are counted over
+	Window time.Duration
+	// Failed attempts of a user or client ip within the window before back-off is applied,
+	// no back-off when zero
+	BackoffThreshold int
+	// Delay after reaching the back-off threshold, doubled for every failed attempt after it
+	BaseDelay time.Duration
+	// Longest delay back-off applies, no maximum when zero
+	MaxDelay time.Duration
+	// Failed attempts of a user within the window before their account is locked, accounts
+	// aren't locked when zero
+	LockoutThreshold int
+	// How long an account stays locked
+	LockoutDuration time.Duration
+}
+
+// Delay will get how long after the last failed attempt the next attempt is allowed
+func (p LockoutPolicy) Delay(failures int) time.Duration {
+	if p.BackoffThreshold == 0 || failures < p.BackoffThreshold {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := p.BackoffThreshold; i < failures; i++ {
+		if (p.MaxDelay > 0 && delay >= p.MaxDelay) || delay > math.MaxInt64/2 {
+			break
+		}
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		return p.MaxDelay
+	}
+
+	return delay
+}
+
+// LockoutStatus holds the failed attempts of a user and client ip
+type LockoutStatus struct {
+	UserId   string
+	ClientIP string
+	// time the account is locked until, zero when it isn't locked
+	LockedUntil  time.Time
+	UserFailures int
+	IPFailures   int
+	// time back-off allows the next attempt at, zero when an attempt is allowed now
+	RetryAt time.Time
+}
+
+// userSubject and ipSubject name the subjects failed attempts are tracked against
+func userSubject(userId string) string {
+	return "user:" + userId
+}
+
+func ipSubject(clientIP string) string {
+	return "ip:" + clientIP
+}
+
+// lockoutStatus will get the failed attempts of a user and client ip, the client ip is
+// ignored when empty
+func (s *Service) lockoutStatus(userId, clientIP string) (*LockoutStatus, error) {
+	policy := s.opt.LockoutPolicy
+	status := &LockoutStatus{UserId: userId, ClientIP: clientIP}
+	now := time.Now()
+
+	lockedUntil, err := s.repo.GetLockout(userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get lockout for user: %s: %w", userId, err)
+	}
+	status.LockedUntil = lockedUntil
+
+	userFailures, err := s.repo.GetFailures(userSubject(userId), policy.Window)
+	if err != nil {
+		return nil, fmt.Errorf("could not get failures for user: %s: %w", userId, err)
+	}
+	status.UserFailures = userFailures.Count
+
+	if retryAt := userFailures.Last.Add(policy.Delay(userFailures.Count)); retryAt.After(now) {
+		status.RetryAt = retryAt
+	}
+
+	if clientIP == "" {
+		return status, nil
+	}
+
+	ipFailures, err := s.repo.GetFailures(ipSubject(clientIP), policy.Window)
+	if err != nil {
+		return nil, fmt.Errorf("could not get failures for client ip: %s: %w", clientIP, err)
+	}
+	status.IPFailures = ipFailures.Count
+
+	if retryAt := ipFailures.Last.Add(policy.Delay(ipFailures.Count)); retryAt.After(now) &&
+		retryAt.After(status.RetryAt) {
+		status.RetryAt = retryAt
+	}
+
+	return status, nil
+}
+
+// checkLockout will make sure a challenge can be attempted for a user from a device
+func (s *Service) checkLockout(userId string, dev Device) error {
+	if s.opt.LockoutPolicy.Window == 0 {
+		return nil
+	}
+
+	status, err := s.lockoutStatus(userId, dev.ClientIP)
+	if err != nil {
+		return err
+	}
+
+	if !status.LockedUntil.IsZero() {
+		return &RetryError{Err: ErrAccountLocked, RetryAt: status.LockedUntil}
+	}
+
+	if !status.RetryAt.IsZero() {
+		return &RetryError{Err: ErrTooManyAttempts, RetryAt: status.RetryAt}
+	}
+
+	return nil
+}
+
+// recordFailure will record a failed challenge of a user from a device, locking the account
+// once it crosses the lockout threshold
+func (s *Service) recordFailure(userId string, dev Device) error {
+	policy := s.opt.LockoutPolicy
+	if policy.Window == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if dev.ClientIP != "" {
+		if _, err := s.repo.AddFailure(ipSubject(dev.ClientIP), now, policy.Window); err != nil {
+			return fmt.Errorf("could not add failure for client ip: %s: %w", dev.ClientIP, err)
+		}
+	}
+
+	failures, err := s.repo.AddFailure(userSubject(userId), now, policy.Window)
+	if err != nil {
+		return fmt.Errorf("could not add failure for user: %s: %w", userId, err)
+	}
+
+	if policy.LockoutThreshold == 0 || failures.Count < policy.LockoutThreshold {
+		return nil
+	}
+
+	if err = s.repo.SetLockout(userId, now.Add(policy.LockoutDuration)); err != nil {
+		return fmt.Errorf("could not lock account of user: %s: %w", userId, err)
+	}
+
+	// the failures that caused the lockout shouldn't lock the account again once it expires
+	if err = s.repo.ClearFailures(userSubject(userId)); err != nil {
+		return fmt.Errorf("could not clear failures for user: %s: %w", userId, err)
+	}
+
+	s.emit(EventAccountLocked, userId, "", dev)
+
+	return nil
+}
+
+// clearFailures will forget the failed attempts of a user after they passed every factor
+func (s *Service) clearFailures(userId string) error {
+	if s.opt.LockoutPolicy.Window == 0 {
+		return nil
+	}
+
+	if err := s.repo.ClearFailures(userSubject(userId)); err != nil {
+		return fmt.Errorf("could not clear failures for user: %s: %w", userId, err)
+	}
+
+	return nil
+}
+
+// Lockout will get the failed attempts and lockout of a user along with the failed attempts
+// of a client ip, the client ip can be empty
+func (s *Service) Lockout(ctx context.Context, userId, clientIP string) (*LockoutStatus,
+	error) {
+	if userId == "" {
+		return nil, ErrInvalidUserId
+	}
+
+	s.repo.WithContext(ctx)
+	return s.lockoutStatus(userId, clientIP)
+}
+
+// ClearLockout will unlock the account of a user and forget their failed attempts along with
+// the failed attempts of a client ip, the client ip can be empty
+func (s *Service) ClearLockout(ctx context.Context, userId, clientIP string) error {
+	if userId == "" {
+		return ErrInvalidUserId
+	}
+
+	s.repo.WithContext(ctx)
+	if err := s.repo.RemoveLockout(userId); err != nil {
+		return fmt.Errorf("could not remove lockout for user: %s: %w", userId, err)
+	}
+
+	if err := s.repo.ClearFailures(userSubject(userId)); err != nil {
+		return fmt.Errorf("could not clear failures for user: %s: %w", userId, err)
+	}
+
+	if clientIP == "" {
+		return nil
+	}
+
+	if err := s.repo.ClearFailures(ipSubject(clientIP)); err != nil {
+		return fmt.Errorf("could not clear failures for client ip: %s: %w", clientIP, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,293 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/repository"
+	"github.com/joshturge-io/auth/pkg/repository/redis"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+var (
+	ErrMFARequired         = errors.New("multi-factor authentication is required")
+	ErrMFAEnabled          = errors.New("multi-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("multi-factor authentication is not enabled")
+	ErrMFANotEnrolled      = errors.New("totp enrollment has not been started")
+	ErrInvalidMFACode      = errors.New("mfa code is not valid")
+	ErrInvalidMFAChallenge = errors.New("mfa challenge is not valid")
+)
+
+const (
+	// totpSkew is the number of time steps either side of now a totp code is accepted for
+	totpSkew = 1
+	// maxMFAAttempts is the number of invalid codes an mfa challenge accepts before it is
+	// removed
+	maxMFAAttempts = 5
+)
+
+// TOTPEnrollment is the totp secret a user adds to their authenticator app
+type TOTPEnrollment struct {
+	Secret string
+	// otpauth uri of the secret that can be shown as a QR code
+	URI string
+}
+
+// mfaEnabled will check if a user has a second factor enabled
+func (s *Service) mfaEnabled(userId string) (bool, error) {
+	mfa, err := s.repo.GetMFA(userId)
+	if err != nil {
+		return false, fmt.Errorf("could not get mfa for user: %s: %w", userId, err)
+	}
+
+	return mfa.Secret != "", nil
+}
+
+// verifyTOTP will check a totp code against an encrypted secret, a code can only be used once
+func (s *Service) verifyTOTP(userId, secret, code string) error {
+	plain, err := s.chall.Decrypt(secret)
+	if err != nil {
+		return fmt.Errorf("could not decrypt totp secret: %w", err)
+	}
+
+	step, ok, err := token.ValidateTOTP(string(plain), code, time.Now(), totpSkew)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := s.repo.UseTOTPStep(userId, step)
+	if err != nil {
+		return fmt.Errorf("could not set totp step for user: %s: %w", userId, err)
+	}
+
+	if !fresh {
+		return fmt.Errorf("totp code has already been used: %w", ErrInvalidMFACode)
+	}
+
+	return nil
+}
+
+// verifyMFACode will check a code with verify unless the user or client ip of the device is
+// locked out, invalid codes are recorded against both like failed challenges
+func (s *Service) verifyMFACode(userId string, dev Device, verify func() error) error {
+	if err := s.checkLockout(userId, dev); err != nil {
+		return err
+	}
+
+	if err := verify(); err != nil {
+		if !errors.Is(err, ErrInvalidMFACode) {
+			return err
+		}
+
+		if recErr := s.recordFailure(userId, dev); recErr != nil {
+			return recErr
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// EnrollTOTP will generate a new totp secret for a user, the secret isn't used until it is
+// confirmed with a code from it
+func (s *Service) EnrollTOTP(ctx context.Context, userId string) (*TOTPEnrollment, error) {
+	s.repo.WithContext(ctx)
+	enabled, err := s.mfaEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return nil, ErrMFAEnabled
+	}
+
+	secret, err := token.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate totp secret: %w", err)
+	}
+
+	encrypted, err := s.chall.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, fmt.Errorf("could not encrypt totp secret: %w", err)
+	}
+
+	if err = s.repo.SetPendingTOTP(userId, encrypted); err != nil {
+		return nil, fmt.Errorf("could not set pending totp for user: %s: %w", userId, err)
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    token.TOTPURI(s.opt.MFAIssuer, userId, secret),
+	}, nil
+}
+
+// ConfirmTOTP will enable the pending totp secret of a user provided a valid code from it,
+// returns the recovery codes generated for the user. Invalid codes are recorded like failed
+// challenges
+func (s *Service) ConfirmTOTP(ctx context.Context, userId, code string,
+	dev Device) ([]string, error) {
+	s.repo.WithContext(ctx)
+	mfa, err := s.repo.GetMFA(userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get mfa for user: %s: %w", userId, err)
+	}
+
+	if mfa.Secret != "" {
+		return nil, ErrMFAEnabled
+	}
+
+	if mfa.Pending == "" {
+		return nil, ErrMFANotEnrolled
+	}
+
+	if err = s.verifyMFACode(userId, dev, func() error {
+		return s.verifyTOTP(userId, mfa.Pending, code)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.EnableTOTP(userId, mfa.Pending); err != nil {
+		return nil, fmt.Errorf("could not enable totp for user: %s: %w", userId, err)
+	}
+
+	return s.generateRecoveryCodes(userId)
+}
+
+// DisableTOTP will remove the totp secret and recovery codes of a user provided a valid code
+// from it or one of their recovery codes. Invalid codes are recorded like failed challenges
+func (s *Service) DisableTOTP(ctx context.Context, userId, code string, dev Device) error {
+	s.repo.WithContext(ctx)
+	mfa, err := s.repo.GetMFA(userId)
+	if err != nil {
+		return fmt.Errorf("could not get mfa for user: %s: %w", userId, err)
+	}
+
+	if mfa.Secret == "" {
+		return ErrMFANotEnabled
+	}
+
+	if err = s.verifyMFACode(userId, dev, func() error {
+		return s.verifySecondFactor(userId, mfa, code)
+	}); err != nil {
+		return err
+	}
+
+	if err = s.repo.RemoveTOTP(userId); err != nil {
+		return fmt.Errorf("could not remove totp for user: %s: %w", userId, err)
+	}
+
+	return nil
+}
+
+// Login will create a session for a user provided a valid challenge. If the user has a second
+// factor enabled no session is created, instead an mfa challenge token is returned that can be
+// exchanged for a session with VerifyMFA
+func (s *Service) Login(ctx context.Context, userId, password string,
+	dev Device) (*Session, string, error) {
+	if err := s.checkChallenge(ctx, userId, password, dev); err != nil {
+		return nil, "", err
+	}
+
+	enabled, err := s.mfaEnabled(userId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !enabled {
+		if err = s.clearFailures(userId); err != nil {
+			return nil, "", err
+		}
+
+		session, err := s.newSession(ctx, userId, dev)
+		return session, "", err
+	}
+
+	mfaToken, err := token.GenerateRefresh(s.opt.RefreshTokenLength)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not generate mfa challenge: %w", err)
+	}
+
+	// only a hash of the challenge token is stored, the same as refresh tokens
+	if err = s.repo.SetMFAChallenge(&repository.MFAChallenge{
+		Id:         s.hashRefresh(mfaToken),
+		UserId:     userId,
+		UserAgent:  dev.UserAgent,
+		ClientIP:   dev.ClientIP,
+		Expiration: time.Now().Add(s.opt.MFAChallengeExpiration),
+	}); err != nil {
+		return nil, "", fmt.Errorf("could not set mfa challenge for user: %s: %w", userId, err)
+	}
+
+	return nil, mfaToken, nil
+}
+
+// VerifyMFA will exchange an mfa challenge token and a valid totp code or recovery code for a
+// session. The challenge is removed once it is used or after too many invalid codes, invalid
+// codes are also recorded against the user and the client ip of the device like failed
+// challenges
+func (s *Service) VerifyMFA(ctx context.Context, mfaToken, code string,
+	dev Device) (*Session, error) {
+	s.repo.WithContext(ctx)
+	id := s.hashRefresh(mfaToken)
+	challenge, err := s.repo.GetMFAChallenge(id)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotExist) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("could not get mfa challenge: %w", err)
+	}
+
+	mfa, err := s.repo.GetMFA(challenge.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get mfa for user: %s: %w", challenge.UserId, err)
+	}
+
+	if mfa.Secret == "" {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err = s.verifyMFACode(challenge.UserId, dev, func() error {
+		return s.verifySecondFactor(challenge.UserId, mfa, code)
+	}); err != nil {
+		if !errors.Is(err, ErrInvalidMFACode) {
+			return nil, err
+		}
+
+		attempts, incrErr := s.repo.IncrMFAChallenge(id)
+		if incrErr != nil && !errors.Is(incrErr, redis.ErrNotExist) {
+			return nil, fmt.Errorf("could not increment mfa challenge: %w", incrErr)
+		}
+
+		if attempts >= maxMFAAttempts {
+			if err := s.repo.RemoveMFAChallenge(id); err != nil {
+				return nil, fmt.Errorf("could not remove mfa challenge: %w", err)
+			}
+		}
+
+		return nil, err
+	}
+
+	// taking the challenge makes sure only one of concurrent verifications gets a session
+	if challenge, err = s.repo.TakeMFAChallenge(id); err != nil {
+		if errors.Is(err, redis.ErrNotExist) {
+			return nil, ErrInvalidMFAChallenge
+		}
+		return nil, fmt.Errorf("could not take mfa challenge: %w", err)
+	}
+
+	if err = s.clearFailures(challenge.UserId); err != nil {
+		return nil, err
+	}
+
+	return s.newSession(ctx, challenge.UserId, Device{
+		UserAgent: challenge.UserAgent,
+		ClientIP:  challenge.ClientIP,
+	})
+}
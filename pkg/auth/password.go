@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+var ErrWeakPassword = errors.New("password does not meet policy")
+
+// PasswordPolicy holds the requirements a password must meet
+type PasswordPolicy struct {
+	// Minimum amount of characters
+	MinLength int
+	// Maximum amount of characters, no maximum when zero
+	MaxLength int
+	// Require at least one upper case letter
+	RequireUpper bool
+	// Require at least one lower case letter
+	RequireLower bool
+	// Require at least one digit
+	RequireDigit bool
+	// Require at least one symbol or punctuation character
+	RequireSymbol bool
+}
+
+// Check a password against the policy, returns ErrWeakPassword with the failed requirement
+func (p PasswordPolicy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+	switch {
+	case length == 0:
+		return fmt.Errorf("password is empty: %w", ErrWeakPassword)
+	case length < p.MinLength:
+		return fmt.Errorf("password must be at least %d characters: %w", p.MinLength,
+			ErrWeakPassword)
+	case p.MaxLength > 0 && length > p.MaxLength:
+		return fmt.Errorf("password must be at most %d characters: %w", p.MaxLength,
+			ErrWeakPassword)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	switch {
+	case p.RequireUpper && !upper:
+		return fmt.Errorf("password must contain an upper case letter: %w", ErrWeakPassword)
+	case p.RequireLower && !lower:
+		return fmt.Errorf("password must contain a lower case letter: %w", ErrWeakPassword)
+	case p.RequireDigit && !digit:
+		return fmt.Errorf("password must contain a digit: %w", ErrWeakPassword)
+	case p.RequireSymbol && !symbol:
+		return fmt.Errorf("password must contain a symbol: %w", ErrWeakPassword)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/joshturge-io/auth/pkg/repository"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// recoveryCodeCount is the number of recovery codes generated for a user at a time
+const recoveryCodeCount = 10
+
+// MFAStatus describes the second factors a user has set up
+type MFAStatus struct {
+	TOTPEnabled bool
+	// number of unused recovery codes
+	RecoveryCodes int
+}
+
+// hashRecoveryCode will hash a recovery code with a salt the same way as passwords
+func hashRecoveryCode(salt []byte, code string) string {
+	return hex.EncodeToString(generateChallengeHash(salt,
+		[]byte(token.NormalizeRecoveryCode(code))))
+}
+
+// generateRecoveryCodes will replace the recovery codes of a user, only hashes of the codes
+// are stored so they can't be shown again
+func (s *Service) generateRecoveryCodes(userId string) ([]string, error) {
+	salt, err := generateRandBytes(s.opt.SaltLength)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := token.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate recovery codes: %w", err)
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashRecoveryCode(salt, code)
+	}
+
+	if err = s.repo.SetRecoveryCodes(userId, hex.EncodeToString(salt), hashes); err != nil {
+		return nil, fmt.Errorf("could not set recovery codes for user: %s: %w", userId, err)
+	}
+
+	return codes, nil
+}
+
+// useRecoveryCode will consume a recovery code of a user, a code can only be used once
+func (s *Service) useRecoveryCode(userId string, mfa *repository.MFA, code string) error {
+	if mfa.RecoverySalt == "" {
+		return ErrInvalidMFACode
+	}
+
+	salt, err := hex.DecodeString(mfa.RecoverySalt)
+	if err != nil {
+		return fmt.Errorf("failed to decode recovery salt: %w", err)
+	}
+
+	used, err := s.repo.UseRecoveryCode(userId, hashRecoveryCode(salt, code))
+	if err != nil {
+		return fmt.Errorf("could not use recovery code for user: %s: %w", userId, err)
+	}
+
+	if !used {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// verifySecondFactor will check a totp code or a recovery code against the enabled second
+// factor of a user
+func (s *Service) verifySecondFactor(userId string, mfa *repository.MFA, code string) error {
+	if isTOTPCode(code) {
+		return s.verifyTOTP(userId, mfa.Secret, code)
+	}
+
+	return s.useRecoveryCode(userId, mfa, code)
+}
+
+// isTOTPCode will check if a code has the format of a totp code rather than a recovery code
+func isTOTPCode(code string) bool {
+	if len(code) != token.TOTPDigits {
+		return false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RegenerateRecoveryCodes will replace the recovery codes of a user provided a valid totp code
+// or one of their current recovery codes. Invalid codes are recorded like failed challenges
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userId, code string,
+	dev Device) ([]string, error) {
+	s.repo.WithContext(ctx)
+	mfa, err := s.repo.GetMFA(userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get mfa for user: %s: %w", userId, err)
+	}
+
+	if mfa.Secret == "" {
+		return nil, ErrMFANotEnabled
+	}
+
+	if err = s.verifyMFACode(userId, dev, func() error {
+		return s.verifySecondFactor(userId, mfa, code)
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.generateRecoveryCodes(userId)
+}
+
+// MFAStatus will get the second factors a user has set up
+func (s *Service) MFAStatus(ctx context.Context, userId string) (*MFAStatus, error) {
+	s.repo.WithContext(ctx)
+	mfa, err := s.repo.GetMFA(userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get mfa for user: %s: %w", userId, err)
+	}
+
+	return &MFAStatus{TOTPEnabled: mfa.Secret != "", RecoveryCodes: mfa.RecoveryCodes}, nil
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+var ErrRoleNotExist = errors.New("role does not exist")
+
+// Role grants a permission level and a set of permissions to the users it is assigned to
+type Role struct {
+	Level       int
+	Permissions []string
+}
+
+// Grants will get the roles of a user along with the permissions and highest permission level
+// they grant. Roles that are no longer defined don't grant anything
+func (s *Service) Grants(ctx context.Context, userId string) (*token.Grants, error) {
+	s.repo.WithContext(ctx)
+	return s.grants(userId)
+}
+
+// grants will get the grants of a user from the roles stored in the repository
+func (s *Service) grants(userId string) (*token.Grants, error) {
+	roles, err := s.repo.GetRoles(userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not get roles for user: %s: %w", userId, err)
+	}
+
+	grants := &token.Grants{Roles: roles}
+	perms := map[string]struct{}{}
+	for _, name := range roles {
+		role, ok := s.opt.Roles[name]
+		if !ok {
+			continue
+		}
+
+		if role.Level > grants.PermLevel {
+			grants.PermLevel = role.Level
+		}
+
+		for _, perm := range role.Permissions {
+			perms[perm] = struct{}{}
+		}
+	}
+
+	for perm := range perms {
+		grants.Permissions = append(grants.Permissions, perm)
+	}
+	sort.Strings(grants.Permissions)
+
+	return grants, nil
+}
+
+// AssignRole will assign a role to a user, the role will be in jwts issued from now on
+func (s *Service) AssignRole(ctx context.Context, userId, role string) error {
+	if _, ok := s.opt.Roles[role]; !ok {
+		return fmt.Errorf("%s: %w", role, ErrRoleNotExist)
+	}
+
+	s.repo.WithContext(ctx)
+	if err := s.userExists(userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.AddRole(userId, role); err != nil {
+		return fmt.Errorf("could not add role: %s to user: %s: %w", role, userId, err)
+	}
+
+	return nil
+}
+
+// RemoveRole will remove a role from a user. Every jwt issued to the user up until now is
+// revoked so the role can't be used any longer, the users sessions can still be renewed
+func (s *Service) RemoveRole(ctx context.Context, userId, role string) error {
+	s.repo.WithContext(ctx)
+	if err := s.userExists(userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveRole(userId, role); err != nil {
+		return fmt.Errorf("could not remove role: %s from user: %s: %w", role, userId, err)
+	}
+
+	return s.revokeTokens(userId)
+}
@@ -2,10 +2,17 @@ package auth
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/joshturge-io/auth/pkg/policy"
 	"github.com/joshturge-io/auth/pkg/repository"
 	"github.com/joshturge-io/auth/pkg/repository/redis"
 	"github.com/joshturge-io/auth/pkg/token"
@@ -16,14 +23,31 @@ var (
 	ErrUserNotExist     = errors.New("user does not exist")
 	ErrInvalidChallenge = errors.New("user provided incorrect challenge")
 	ErrInvalidSession   = errors.New("session is not valid")
+	ErrUserExist        = errors.New("user already exists")
+	ErrInvalidUserId    = errors.New("user id is not valid")
+	ErrSessionNotExist  = errors.New("session does not exist")
+	ErrRefreshReused    = errors.New("refresh token has been reused")
 )
 
+// refreshHashPrefix marks a stored refresh token as a hash rather than the token itself
+const refreshHashPrefix = "hmac-sha256:"
+
+// Device holds information about the client a session is used from
+type Device struct {
+	UserAgent string
+	ClientIP  string
+}
+
 // Session holds information about users session
 type Session struct {
+	Id                string
 	UserId            string
 	Refresh           string
 	RefreshExpiration time.Time
 	JWT               string
+	CreatedAt         time.Time
+	LastUsed          time.Time
+	Device
 }
 
 // Options for tokens
@@ -32,37 +56,73 @@ type Options struct {
 	RefreshTokenLength int
 	// JWT Expiration time
 	JWTokenExpiration time.Duration
+	// Issuer, audience and leeway of jwts, can be nil
+	JWTOptions *token.Options
 	// Refresh Expiration time
 	RefreshTokenExpiration time.Duration
+	// Secret used to hash refresh tokens before they are stored
+	RefreshSecret string
 	// length of password salts
 	SaltLength int
+	// Requirements for new passwords
+	PasswordPolicy PasswordPolicy
+	// Called with every security event, events are dropped when nil
+	EventHandler EventHandler
+	// Roles that can be assigned to users keyed by name
+	Roles map[string]Role
+	// Policy used to authorize actions, every action is denied when nil
+	Policy *policy.Policy
+	// Thresholds after which failed challenges are slowed down or locked out
+	LockoutPolicy LockoutPolicy
+	// Issuer shown in authenticator apps for totp secrets
+	MFAIssuer string
+	// How long a user has to provide their second factor after logging in
+	MFAChallengeExpiration time.Duration
 }
 
 // Service is an authentication service used for manipulating sessions
 type Service struct {
-	repo      repository.DepositWithdrawer
-	chall     *Challenger
-	jwtSecret string
-	opt       *Options
+	repo    repository.DepositWithdrawer
+	chall   *Challenger
+	keyring *token.Keyring
+	opt     *Options
 }
 
-// NewService will create a new auth service
-func NewService(secret string, repo repository.DepositWithdrawer, keys []string,
+// NewService will create a new auth service that signs jwts with the keyrings active key
+func NewService(keyring *token.Keyring, repo repository.DepositWithdrawer, keys []string,
 	opt *Options) *Service {
 	cipherKeys := make([][]byte, len(keys))
 	for i, key := range keys {
 		cipherKeys[i] = []byte(key)
 	}
 
-	return &Service{repo, NewChallenger(opt.SaltLength, cipherKeys), secret, opt}
+	return &Service{repo, NewChallenger(opt.SaltLength, cipherKeys), keyring, opt}
+}
+
+// newSession will generate a brand new session for a user
+func (s *Service) newSession(ctx context.Context, userId string, dev Device) (*Session, error) {
+	id, err := generateRandBytes(16)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate session id: %w", err)
+	}
+
+	return s.generateSession(ctx, &repository.Session{
+		Id:        hex.EncodeToString(id),
+		UserId:    userId,
+		CreatedAt: time.Now(),
+		UserAgent: dev.UserAgent,
+		ClientIP:  dev.ClientIP,
+	})
 }
 
-// generateSession will generate a new session
-func (s *Service) generateSession(ctx context.Context, userId string) (*Session, error) {
+// generateSession will generate new tokens for a session record and store the record. If the
+// record already has a refresh token the session is rotated with it as the parent
+func (s *Service) generateSession(ctx context.Context, rec *repository.Session) (*Session,
+	error) {
 	s.repo.WithContext(ctx)
 	var (
 		ref = make(chan string, 1)
-		jwt = make(chan string, 1)
+		jwt = make(chan *token.JW, 1)
 	)
 	defer func() {
 		close(ref)
@@ -81,107 +141,97 @@ func (s *Service) generateSession(ctx context.Context, userId string) (*Session,
 		return nil
 	})
 	errs.Go(func() error {
-		jw := token.NewJW(s.jwtSecret, userId, s.opt.JWTokenExpiration)
+		grants, err := s.grants(rec.UserId)
+		if err != nil {
+			return err
+		}
+
+		jw := token.NewJW(s.keyring.Active(), s.opt.JWTOptions, rec.UserId,
+			s.opt.JWTokenExpiration)
+		jw.SetGrants(*grants)
 		if err := jw.Generate(); err != nil {
 			return fmt.Errorf("failed to generate jwt: %w", err)
 		}
 
-		jwt <- jw.Token()
+		jwt <- jw
 
 		return nil
 	})
 
 	if err := errs.Wait(); err != nil {
-		if errors.Is(err, redis.ErrNotExist) {
-			return nil, ErrUserNotExist
-		}
 		return nil, err
 	}
 
-	refresh := <-ref
-
-	if err := s.repo.SetRefreshToken(userId, refresh,
-		s.opt.RefreshTokenExpiration); err != nil {
-		return nil, fmt.Errorf("could not set refresh token: %w", err)
+	refresh, jw := <-ref, <-jwt
+	rec.Parent = rec.Refresh
+	rec.Refresh = s.hashRefresh(refresh)
+	rec.JWTId = jw.Id()
+	rec.JWTExpiration = time.Now().Add(jw.ExpiresIn())
+	rec.LastUsed = time.Now()
+	rec.Expiration = rec.LastUsed.Add(s.opt.RefreshTokenExpiration)
+
+	if rec.Parent == "" {
+		if err := s.repo.SetSession(rec); err != nil {
+			return nil, fmt.Errorf("could not set session: %w", err)
+		}
+	} else if err := s.repo.RotateSession(rec); err != nil {
+		if errors.Is(err, repository.ErrStaleRefresh) || errors.Is(err, redis.ErrNotExist) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("could not rotate session: %w", err)
 	}
 
 	return &Session{
-		UserId:            userId,
+		Id:                rec.Id,
+		UserId:            rec.UserId,
 		Refresh:           refresh,
-		RefreshExpiration: time.Now().Add(s.opt.RefreshTokenExpiration),
-		JWT:               <-jwt,
+		RefreshExpiration: rec.Expiration,
+		JWT:               jw.Token(),
+		CreatedAt:         rec.CreatedAt,
+		LastUsed:          rec.LastUsed,
+		Device: Device{
+			UserAgent: rec.UserAgent,
+			ClientIP:  rec.ClientIP,
+		},
 	}, nil
 }
 
-// validateSession will make sure a session has a valid refresh and jw token
-func (s *Service) validateSession(ctx context.Context, sess *Session) error {
-	switch {
-	case sess.UserId == "":
-		return ErrInvalidSession
-	case sess.Refresh == "":
-		return ErrInvalidSession
-	case sess.JWT == "":
-		return ErrInvalidSession
+// checkChallenge will validate a users challenge against the salt and hash in the repository.
+// Failed challenges are recorded against the user and the client ip of the device, returns
+// ErrAccountLocked or ErrTooManyAttempts without checking the challenge once the lockout
+// policy stops further attempts. The users failures are left for the caller to clear once the
+// user has passed every factor
+func (s *Service) checkChallenge(ctx context.Context, userId, password string,
+	dev Device) error {
+	if userId == "" || password == "" {
+		return ErrInvalidChallenge
 	}
 
-	validity := make(chan bool, 2)
-	defer close(validity)
-
-	errs, ctx := errgroup.WithContext(ctx)
-	errs.Go(func() error {
-		valid, err := s.IsValidRefresh(ctx, sess.UserId, sess.Refresh)
-		if err != nil {
-			return err
-		}
-
-		validity <- valid
-
-		return nil
-	})
-	errs.Go(func() error {
-		blacklisted, err := s.repo.IsBlacklisted(sess.JWT)
-		if err != nil {
-			return fmt.Errorf("unable to check blacklist status of token: %w", err)
-		}
-		valid, err := s.IsValidJWT(sess.JWT)
-		if err != nil {
-			return err
-		}
-
-		validity <- valid && !blacklisted
-
-		return nil
-	})
-
-	if err := errs.Wait(); err != nil {
-		return fmt.Errorf("failed to validate: %w", err)
+	s.repo.WithContext(ctx)
+	if err := s.checkLockout(userId, dev); err != nil {
+		return err
 	}
 
-	// check if either goroutine found and returned an invalid token
-	if !<-validity || !<-validity {
-		return ErrInvalidSession
+	err := s.validateChallenge(ctx, userId, password)
+	if errors.Is(err, ErrInvalidChallenge) || errors.Is(err, ErrUserNotExist) {
+		if recErr := s.recordFailure(userId, dev); recErr != nil {
+			return recErr
+		}
+		return err
 	}
 
-	return nil
+	return err
 }
 
-// SessionWithChallenge create a new session provided a valid challenge (username and password)
-func (s *Service) SessionWithChallenge(ctx context.Context, userId, password string) (*Session,
-	error) {
-	if userId == "" || password == "" {
-		return nil, ErrInvalidChallenge
-	}
-
-	s.repo.WithContext(ctx)
+// validateChallenge will check a password against the salt and hash of a user
+func (s *Service) validateChallenge(ctx context.Context, userId, password string) error {
 	var (
-		saltChan    = make(chan string, 1)
-		hashChan    = make(chan string, 1)
-		sessionChan = make(chan *Session, 1)
+		saltChan = make(chan string, 1)
+		hashChan = make(chan string, 1)
 	)
 	defer func() {
 		close(saltChan)
 		close(hashChan)
-		close(sessionChan)
 	}()
 
 	errs, ctx := errgroup.WithContext(ctx)
@@ -207,54 +257,233 @@ func (s *Service) SessionWithChallenge(ctx context.Context, userId, password str
 
 		return nil
 	})
-	errs.Go(func() error {
-		session, err := s.generateSession(ctx, userId)
-		if err != nil {
-			return err
-		}
-
-		sessionChan <- session
-
-		return nil
-	})
 
 	if err := errs.Wait(); err != nil {
 		if errors.Is(err, redis.ErrNotExist) {
-			return nil, ErrUserNotExist
+			return ErrUserNotExist
 		}
-		return nil, err
+		return err
 	}
 
 	if valid, err := s.chall.Validate(<-saltChan, password, <-hashChan); !valid {
 		if err != nil {
-			return nil, fmt.Errorf("failed to validate challenge: %w", err)
+			return fmt.Errorf("failed to validate challenge: %w", err)
+		}
+
+		return ErrInvalidChallenge
+	}
+
+	return nil
+}
+
+// SessionWithChallenge create a new session provided a valid challenge (username and password).
+// Nothing is persisted to the repository until the challenge has been validated. Returns
+// ErrMFARequired if the user has a second factor enabled, Login should be used instead
+func (s *Service) SessionWithChallenge(ctx context.Context, userId, password string,
+	dev Device) (*Session, error) {
+	if err := s.checkChallenge(ctx, userId, password, dev); err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.mfaEnabled(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if enabled {
+		return nil, ErrMFARequired
+	}
+
+	if err = s.clearFailures(userId); err != nil {
+		return nil, err
+	}
+
+	return s.newSession(ctx, userId, dev)
+}
+
+// userIdPattern is the set of user ids that can be registered. Ids are part of repository
+// keys so they can't contain the ':' separating the parts of a key
+var userIdPattern = regexp.MustCompile(`^[A-Za-z0-9._@-]{1,64}$`)
+
+// Register will create a new user provided a challenge that meets the password policy. The
+// user id must be 1 to 64 letters, digits, '.', '_', '-' or '@'. If newSession is true a
+// session will be created for the new user, otherwise the returned session is nil
+func (s *Service) Register(ctx context.Context, userId, password string, newSession bool,
+	dev Device) (*Session, error) {
+	if !userIdPattern.MatchString(userId) {
+		return nil, ErrInvalidUserId
+	}
+
+	if err := s.opt.PasswordPolicy.Check(password); err != nil {
+		return nil, err
+	}
+
+	s.repo.WithContext(ctx)
+	salt, hash, err := s.chall.Generate(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	if err = s.repo.CreateUser(userId, salt, hash); err != nil {
+		if errors.Is(err, repository.ErrUserExist) {
+			return nil, ErrUserExist
 		}
+		return nil, fmt.Errorf("could not create user: %s: %w", userId, err)
+	}
 
-		return nil, ErrInvalidChallenge
+	if !newSession {
+		return nil, nil
 	}
 
-	return <-sessionChan, nil
+	return s.newSession(ctx, userId, dev)
 }
 
-// IsValidRefresh will query the repository and validate that it exists, if it doesn't then the
-// token is invalid
-func (s *Service) IsValidRefresh(ctx context.Context, userId, refresh string) (bool, error) {
+// ChangePassword will replace a users password provided their current password is valid. All
+// of the users outstanding refresh tokens and jwts are revoked
+func (s *Service) ChangePassword(ctx context.Context, userId, oldPassword, newPassword string,
+	dev Device) error {
+	if err := s.checkChallenge(ctx, userId, oldPassword, dev); err != nil {
+		return err
+	}
+
+	if err := s.clearFailures(userId); err != nil {
+		return err
+	}
+
+	return s.ResetPassword(ctx, userId, newPassword)
+}
+
+// ResetPassword will replace a users password without needing the current password. All of
+// the users outstanding refresh tokens and jwts are revoked
+func (s *Service) ResetPassword(ctx context.Context, userId, newPassword string) error {
+	if userId == "" {
+		return ErrInvalidUserId
+	}
+
+	if err := s.opt.PasswordPolicy.Check(newPassword); err != nil {
+		return err
+	}
+
 	s.repo.WithContext(ctx)
-	userRefresh, err := s.repo.GetRefreshToken(userId)
+	if err := s.userExists(userId); err != nil {
+		return err
+	}
+
+	salt, hash, err := s.chall.Generate(newPassword)
 	if err != nil {
+		return fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	if err = s.repo.SetChallenge(userId, salt, hash); err != nil {
+		return fmt.Errorf("could not set challenge for user: %s: %w", userId, err)
+	}
+
+	return s.revokeUser(ctx, userId)
+}
+
+// userExists will check a user has been registered, returns ErrUserNotExist if they haven't.
+// Revoking tokens of an unknown user would create a record that blocks registering them
+func (s *Service) userExists(userId string) error {
+	if _, err := s.repo.GetSalt(userId); err != nil {
 		if errors.Is(err, redis.ErrNotExist) {
-			return false, ErrUserNotExist
+			return ErrUserNotExist
+		}
+		return fmt.Errorf("could not get salt for user: %s from repository: %w", userId, err)
+	}
+
+	return nil
+}
+
+// revokeUser will remove all of the users sessions and revoke every jwt issued to the user up
+// until now, the user must exist
+func (s *Service) revokeUser(ctx context.Context, userId string) error {
+	if err := s.revokeTokens(userId); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveSessions(userId); err != nil {
+		return fmt.Errorf("could not remove sessions: %w", err)
+	}
+
+	return nil
+}
+
+// revokeTokens will revoke every jwt issued to a user up until now. Revocations only have
+// second precision, so the jwt last issued to each session is also blacklisted to catch jwts
+// issued earlier in the same second
+func (s *Service) revokeTokens(userId string) error {
+	if err := s.repo.SetRevocation(userId, time.Now()); err != nil {
+		return fmt.Errorf("could not revoke tokens for user: %s: %w", userId, err)
+	}
+
+	recs, err := s.repo.GetSessions(userId)
+	if err != nil {
+		return fmt.Errorf("could not get sessions for user: %s: %w", userId, err)
+	}
+
+	for _, rec := range recs {
+		if err = s.blacklistRecord(rec); err != nil {
+			return err
 		}
-		return false, fmt.Errorf("unable to get refresh token for userId: %s: %w", userId, err)
 	}
 
-	return userRefresh == refresh, nil
+	return nil
+}
+
+// IsValidRefresh will query the repository for the session and validate that the refresh
+// token matches, if the session doesn't exist then the token is invalid
+func (s *Service) IsValidRefresh(ctx context.Context, userId, sessionId,
+	refresh string) (bool, error) {
+	_, valid, err := s.getSession(ctx, userId, sessionId, refresh)
+	return valid, err
+}
+
+// getSession will get a session record from the repository and check it against a refresh
+// token, the record is nil if the session doesn't exist
+func (s *Service) getSession(ctx context.Context, userId, sessionId,
+	refresh string) (*repository.Session, bool, error) {
+	s.repo.WithContext(ctx)
+	rec, err := s.repo.GetSession(userId, sessionId)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotExist) || errors.Is(err, redis.ErrTokenExpired) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("unable to get session: %s for userId: %s: %w",
+			sessionId, userId, err)
+	}
+
+	return rec, s.matchRefresh(rec.Refresh, refresh), nil
+}
+
+// hashRefresh will create the keyed hash of a refresh token that gets stored in place of the
+// token itself
+func (s *Service) hashRefresh(refresh string) string {
+	mac := hmac.New(sha256.New, []byte(s.opt.RefreshSecret))
+	mac.Write([]byte(refresh))
+
+	return refreshHashPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// matchRefresh will check in constant time if a refresh token matches a stored one. Tokens
+// stored before they were hashed are compared as they are, they get replaced with a hash the
+// next time the session is renewed
+func (s *Service) matchRefresh(stored, refresh string) bool {
+	if !strings.HasPrefix(stored, refreshHashPrefix) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(refresh)) == 1
+	}
+
+	return hmac.Equal([]byte(stored), []byte(s.hashRefresh(refresh)))
+}
+
+// KeySet will get the public keys jwts issued by the service can be verified with
+func (s *Service) KeySet() *token.JWKS {
+	return s.keyring.JWKS()
 }
 
 // IsValidJWT will attempt to parse the jwt and check if it has expired. If it fails to parse
 // or has expired then the jwt is invalid
 func (s *Service) IsValidJWT(tokenStr string) (bool, error) {
-	t, err := token.NewJWFromExisting(s.jwtSecret, tokenStr)
+	t, err := token.NewJWFromExisting(s.keyring, s.opt.JWTOptions, tokenStr)
 	if err != nil {
 		return false, err
 	}
@@ -262,81 +491,312 @@ func (s *Service) IsValidJWT(tokenStr string) (bool, error) {
 	return !t.IsExpired(), nil
 }
 
-// DestroySession will invalidate a session by blacklisting the jwt and removing the refresh
-// token from the users record
-func (s *Service) DestroySession(ctx context.Context, old *Session) error {
+// IsRevokedJWT will check if a jwt has been blacklisted or was issued before all of the users
+// tokens were revoked
+func (s *Service) IsRevokedJWT(ctx context.Context, tokenStr string) (bool, error) {
+	t, err := token.NewJWFromExisting(s.keyring, s.opt.JWTOptions, tokenStr)
+	if err != nil {
+		return false, err
+	}
+
 	s.repo.WithContext(ctx)
-	if err := s.validateSession(ctx, old); err != nil {
+	return s.isRevoked(t)
+}
+
+// isRevoked will check if a parsed jwt has been blacklisted or was issued before all of the
+// users tokens were revoked
+func (s *Service) isRevoked(t *token.JW) (bool, error) {
+	blacklisted, err := s.repo.IsBlacklisted(tokenId(t))
+	if err != nil {
+		return false, fmt.Errorf("unable to check blacklist status of token: %w", err)
+	}
+
+	if blacklisted {
+		return true, nil
+	}
+
+	revoked, err := s.repo.GetRevocation(t.Username())
+	if err != nil {
+		return false, fmt.Errorf("unable to check revocation status of token: %w", err)
+	}
+
+	// iat only has second precision, jwts issued earlier in the second of the revocation were
+	// blacklisted by revokeTokens
+	return !revoked.IsZero() && t.IssuedAt().Before(revoked), nil
+}
+
+// tokenId will get the id a jwt is blacklisted by, tokens issued before the jti claim was added
+// are identified by a hash of the token
+func tokenId(t *token.JW) string {
+	if t.Id() != "" {
+		return t.Id()
+	}
+
+	sum := sha256.Sum256([]byte(t.Token()))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// DestroySession will invalidate a session by blacklisting the jwt and removing the session
+// from the users record. The session is authenticated by its refresh token so it can still be
+// destroyed after its jwt has expired
+func (s *Service) DestroySession(ctx context.Context, old *Session) error {
+	if old.UserId == "" || old.Id == "" || old.Refresh == "" {
+		return ErrInvalidSession
+	}
+
+	rec, valid, err := s.getSession(ctx, old.UserId, old.Id, old.Refresh)
+	if err != nil {
 		return err
 	}
 
-	errs, ctx := errgroup.WithContext(ctx)
-	errs.Go(func() error {
-		if err := s.repo.RemoveRefreshToken(old.UserId); err != nil {
-			if errors.Is(err, redis.ErrNotExist) {
-				return ErrUserNotExist
-			}
-			return fmt.Errorf("failed to remove refresh token: %w", err)
+	if rec == nil || !valid {
+		return ErrInvalidSession
+	}
+
+	// a jwt that doesn't belong to the user fails the logout, expired jwts are ignored
+	if old.JWT != "" {
+		if err := s.blacklist(old.UserId, old.JWT); err != nil &&
+			!errors.Is(err, token.ErrJWExpired) {
+			return err
 		}
+	}
+	// the jwt last issued to the session isn't always the one presented
+	if err := s.blacklistRecord(rec); err != nil {
+		return err
+	}
+
+	if err := s.repo.RemoveSession(old.UserId, old.Id); err != nil {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+
+	return nil
+}
+
+// blacklist a jwt belonging to a user until it expires
+func (s *Service) blacklist(userId, tokenStr string) error {
+	jw, err := token.NewJWFromExisting(s.keyring, s.opt.JWTOptions, tokenStr)
+	if err != nil {
+		return err
+	}
+
+	if jw.Username() != userId {
+		return ErrInvalidSession
+	}
+
+	if err := s.repo.SetBlacklist(tokenId(jw), jw.ExpiresIn()); err != nil {
+		return fmt.Errorf("could not blacklist token %w", err)
+	}
+
+	return nil
+}
 
+// blacklistRecord will blacklist the jwt last issued to a session record by its jti, expired
+// jwts are ignored
+func (s *Service) blacklistRecord(rec *repository.Session) error {
+	exp := time.Until(rec.JWTExpiration)
+	if rec.JWTId == "" || exp <= 0 {
 		return nil
-	})
-	errs.Go(func() error {
-		jw, err := token.NewJWFromExisting(s.jwtSecret, old.JWT)
-		if err != nil {
-			return err
+	}
+
+	if err := s.repo.SetBlacklist(rec.JWTId, exp); err != nil {
+		return fmt.Errorf("could not blacklist token %w", err)
+	}
+
+	return nil
+}
+
+// Identity of the user a jwt was issued to
+type Identity struct {
+	UserId string
+	token.Grants
+}
+
+// Identify will validate a jwt and make sure it hasn't been revoked, returns the identity
+// held by the jwt
+func (s *Service) Identify(ctx context.Context, tokenStr string) (*Identity, error) {
+	jw, err := token.NewJWFromExisting(s.keyring, s.opt.JWTOptions, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if jw.IsExpired() {
+		return nil, token.ErrJWExpired
+	}
+
+	s.repo.WithContext(ctx)
+	revoked, err := s.isRevoked(jw)
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, fmt.Errorf("token has been revoked: %w", token.ErrJWInvalid)
+	}
+
+	return &Identity{
+		UserId: jw.Username(),
+		Grants: jw.Grants(),
+	}, nil
+}
+
+// Authenticate will validate a jwt and make sure it hasn't been revoked, returns the user id
+// the jwt was issued to
+func (s *Service) Authenticate(ctx context.Context, tokenStr string) (string, error) {
+	identity, err := s.Identify(ctx, tokenStr)
+	if err != nil {
+		return "", err
+	}
+
+	return identity.UserId, nil
+}
+
+// ListSessions will get every active session of a user, the refresh token and jwt of each
+// session are left out
+func (s *Service) ListSessions(ctx context.Context, userId string) ([]*Session, error) {
+	s.repo.WithContext(ctx)
+	recs, err := s.repo.GetSessions(userId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get sessions for userId: %s: %w", userId, err)
+	}
+
+	sessions := make([]*Session, len(recs))
+	for i, rec := range recs {
+		sessions[i] = &Session{
+			Id:                rec.Id,
+			UserId:            rec.UserId,
+			RefreshExpiration: rec.Expiration,
+			CreatedAt:         rec.CreatedAt,
+			LastUsed:          rec.LastUsed,
+			Device: Device{
+				UserAgent: rec.UserAgent,
+				ClientIP:  rec.ClientIP,
+			},
 		}
-		if err := s.repo.SetBlacklist(jw.Token(), jw.ExpiresIn()); err != nil {
-			return fmt.Errorf("could not blacklist token %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession will remove one of a users sessions and blacklist its jwt
+func (s *Service) RevokeSession(ctx context.Context, userId, sessionId string) error {
+	s.repo.WithContext(ctx)
+	rec, err := s.repo.GetSession(userId, sessionId)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotExist) || errors.Is(err, redis.ErrTokenExpired) {
+			return ErrSessionNotExist
+		}
+		return fmt.Errorf("unable to get session: %s for userId: %s: %w", sessionId, userId,
+			err)
+	}
+
+	errs, ctx := errgroup.WithContext(ctx)
+	errs.Go(func() error {
+		if err := s.repo.RemoveSession(userId, sessionId); err != nil {
+			return fmt.Errorf("failed to remove session: %w", err)
 		}
 
 		return nil
 	})
+	errs.Go(func() error {
+		return s.blacklistRecord(rec)
+	})
 
 	return errs.Wait()
 }
 
-// Renew will delete a current users session and generate them a new one
+// RevokeAllSessions will remove every session of a user and revoke every jwt issued to them
+func (s *Service) RevokeAllSessions(ctx context.Context, userId string) error {
+	s.repo.WithContext(ctx)
+	if err := s.userExists(userId); err != nil {
+		return err
+	}
+
+	return s.revokeUser(ctx, userId)
+}
+
+// revokeReused will revoke a session after one of its rotated refresh tokens was presented
+func (s *Service) revokeReused(ctx context.Context, old *Session) error {
+	s.emit(EventRefreshReuse, old.UserId, old.Id, old.Device)
+
+	if err := s.RevokeSession(ctx, old.UserId, old.Id); err != nil &&
+		!errors.Is(err, ErrSessionNotExist) {
+		return err
+	}
+
+	return ErrRefreshReused
+}
+
+// Renew will rotate the tokens of a users session. The session keeps its id and creation
+// time but takes on the device it was renewed from. Presenting a refresh token that has
+// already been rotated will revoke the session
 func (s *Service) Renew(ctx context.Context, old *Session) (*Session, error) {
-	if old.UserId == "" || old.Refresh == "" {
+	if old.UserId == "" || old.Id == "" || old.Refresh == "" {
 		return nil, ErrInvalidSession
 	}
 
-	errs, ctx := errgroup.WithContext(ctx)
-	if old.JWT != "" {
-		errs.Go(func() error {
-			jw, err := token.NewJWFromExisting(s.jwtSecret, old.JWT)
-			if err != nil {
-				return err
-			}
-
-			if err := s.repo.SetBlacklist(jw.Token(), jw.ExpiresIn()); err != nil {
-				return fmt.Errorf("could not blacklist token %w", err)
-			}
+	rec, valid, err := s.getSession(ctx, old.UserId, old.Id, old.Refresh)
+	if err != nil {
+		return nil, err
+	}
 
-			return nil
-		})
+	if rec == nil {
+		return nil, ErrInvalidSession
 	}
-	errs.Go(func() error {
-		if valid, err := s.IsValidRefresh(ctx, old.UserId, old.Refresh); !valid {
+
+	if !valid {
+		reused, err := s.repo.IsRotatedRefresh(old.UserId, old.Id, s.hashRefresh(old.Refresh))
+		if err != nil {
+			return nil, fmt.Errorf("unable to check if refresh token was rotated: %w", err)
+		}
+
+		// the parent of a session renewed with a plaintext token is also plaintext
+		if !reused {
+			reused, err = s.repo.IsRotatedRefresh(old.UserId, old.Id, old.Refresh)
 			if err != nil {
-				return err
+				return nil, fmt.Errorf("unable to check if refresh token was rotated: %w",
+					err)
 			}
 		}
 
-		if err := s.repo.RemoveRefreshToken(old.UserId); err != nil {
-			if errors.Is(err, redis.ErrNotExist) {
-				return ErrUserNotExist
-			}
-			return fmt.Errorf("could not remove refresh token: %w", err)
+		if reused {
+			return nil, s.revokeReused(ctx, old)
 		}
 
-		return nil
-	})
+		return nil, ErrInvalidSession
+	}
 
-	if err := errs.Wait(); err != nil {
+	if old.UserAgent != "" {
+		rec.UserAgent = old.UserAgent
+	}
+	if old.ClientIP != "" {
+		rec.ClientIP = old.ClientIP
+	}
+
+	// blacklist before renewing so a jwt that doesn't belong to the session fails the renewal,
+	// sessions are usually renewed after their jwt has expired so expired jwts are ignored
+	if old.JWT != "" {
+		if err := s.blacklist(old.UserId, old.JWT); err != nil &&
+			!errors.Is(err, token.ErrJWExpired) {
+			return nil, err
+		}
+	}
+	// the jwt last issued to the session isn't always the one presented
+	if err := s.blacklistRecord(rec); err != nil {
+		return nil, err
+	}
+
+	session, err := s.generateSession(ctx, rec)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrStaleRefresh):
+			// another renewal rotated the refresh token first
+			return nil, s.revokeReused(ctx, old)
+		case errors.Is(err, redis.ErrNotExist):
+			return nil, ErrInvalidSession
+		}
 		return nil, err
 	}
 
-	return s.generateSession(ctx, old.UserId)
+	return session, nil
 }
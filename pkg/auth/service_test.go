@@ -2,18 +2,28 @@ package auth_test
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"sort"
-	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/joshturge-io/auth/pkg/auth"
+	"github.com/joshturge-io/auth/pkg/policy"
 	"github.com/joshturge-io/auth/pkg/repository"
 	"github.com/joshturge-io/auth/pkg/token"
 )
 
 var (
 	srv        *auth.Service
+	events     []*auth.SecurityEvent
+	jwtKey     = token.NewHMACKey("secret")
+	prevKey    = token.NewHMACKey("previous_secret")
 	password   = "123password"
 	cipherKeys = []string{
 		"vcMGBMVbxobHRRdX1WBYq0T4L3UYWQLd",
@@ -24,22 +34,78 @@ var (
 
 func resetRepo() {
 	repository.TestUser = map[string]string{
-		"salt":       "25b072f201ef24e750dcc558eaf2d8f3",
-		"hash":       "1743545c93d519060a72e5671a66cbe898163b41d8be2a92a57ac3b6a2650c8394cf4f009aa0df642721145694879ace89c1a9973ff601538220d6a59f665524022fc789a3f6512d7f4654ff8f39c7ba7ec5b12e93c08df97be9f8a4",
-		"refresh":    "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
-		"expiration": strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10),
+		"salt": "25b072f201ef24e750dcc558eaf2d8f3",
+		"hash": "1743545c93d519060a72e5671a66cbe898163b41d8be2a92a57ac3b6a2650c8394cf4f009aa0df642721145694879ace89c1a9973ff601538220d6a59f665524022fc789a3f6512d7f4654ff8f39c7ba7ec5b12e93c08df97be9f8a4",
+	}
+	repository.TestSessions = map[string]*repository.Session{
+		"session": {
+			Id:         "session",
+			UserId:     "user",
+			Refresh:    "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
+			Expiration: time.Now().Add(24 * time.Hour),
+			CreatedAt:  time.Now(),
+			LastUsed:   time.Now(),
+		},
 	}
 
+	repository.TestUsers = map[string]map[string]string{}
+	repository.TestRotated = map[string][]string{}
 	repository.TestBlacklist = []string{}
+	repository.TestRoles = map[string][]string{}
+	repository.TestMFA = map[string]*repository.MFA{}
+	repository.TestMFAChallenges = map[string]*repository.MFAChallenge{}
+	repository.TestRecoveryCodes = map[string][]string{}
+	repository.TestFailures = map[string][]time.Time{}
+	repository.TestLockouts = map[string]time.Time{}
+	events = nil
 }
 
 func init() {
+	keyring, err := token.NewKeyring(jwtKey, prevKey)
+	if err != nil {
+		panic(err)
+	}
+
+	authPolicy, err := policy.Parse([]byte(`
+rules:
+    - name: "own sessions"
+      effect: "allow"
+      actions: ["sessions:read"]
+      resources: ["users/${sub}/sessions"]
+`))
+	if err != nil {
+		panic(err)
+	}
+
 	repo := repository.NewTestRepository()
-	srv = auth.NewService("secret", repo, cipherKeys, &auth.Options{
+	srv = auth.NewService(keyring, repo, cipherKeys, &auth.Options{
 		RefreshTokenLength:     32,
 		JWTokenExpiration:      15 * time.Minute,
 		RefreshTokenExpiration: 24 * time.Hour,
+		RefreshSecret:          "refresh_secret",
 		SaltLength:             16,
+		PasswordPolicy: auth.PasswordPolicy{
+			MinLength:    8,
+			RequireDigit: true,
+		},
+		EventHandler: func(event *auth.SecurityEvent) {
+			events = append(events, event)
+		},
+		Roles: map[string]auth.Role{
+			"admin": {Level: 100, Permissions: []string{"sessions:revoke", "users:manage"}},
+			"user":  {Level: 10, Permissions: []string{"sessions:read", "sessions:revoke"}},
+		},
+		LockoutPolicy: auth.LockoutPolicy{
+			Window:           time.Hour,
+			BackoffThreshold: 3,
+			BaseDelay:        time.Minute,
+			MaxDelay:         time.Hour,
+			LockoutThreshold: 5,
+			LockoutDuration:  time.Hour,
+		},
+		Policy:                 authPolicy,
+		MFAIssuer:              "auth",
+		MFAChallengeExpiration: 5 * time.Minute,
 	})
 
 	resetRepo()
@@ -48,7 +114,7 @@ func init() {
 func TestSessionWithChallenge(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
 	defer cancel()
-	session, err := srv.SessionWithChallenge(ctx, "user", password)
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
 	if err != nil {
 		t.Error(err)
 		t.FailNow()
@@ -61,7 +127,7 @@ func TestSessionWithChallenge(t *testing.T) {
 func TestDestroySession(t *testing.T) {
 	resetRepo()
 
-	jw := token.NewJW("secret", "user", 15*time.Minute)
+	jw := token.NewJW(jwtKey, nil, "user", 15*time.Minute)
 	if err := jw.Generate(); err != nil {
 		t.Error(err)
 	}
@@ -69,27 +135,32 @@ func TestDestroySession(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
 	defer cancel()
 
-	if err := srv.DestroySession(ctx, &auth.Session{"user", repository.TestUser["refresh"],
-		jw.Token()}); err != nil {
+	if err := srv.DestroySession(ctx, &auth.Session{Id: "session", UserId: "user",
+		Refresh: repository.TestSessions["session"].Refresh, JWT: jw.Token()}); err != nil {
 		t.Error(err)
 		t.FailNow()
 	}
 
 	if i := sort.SearchStrings(repository.TestBlacklist,
-		jw.Token()); repository.TestBlacklist[i] != jw.Token() {
+		jw.Id()); i == len(repository.TestBlacklist) || repository.TestBlacklist[i] != jw.Id() {
 		t.Error("JWT not in repository.TestBlacklist")
 	}
+
+	if _, ok := repository.TestSessions["session"]; ok {
+		t.Error("session was not removed from repository.TestSessions")
+	}
 }
 
 func TestRenew(t *testing.T) {
 	resetRepo()
-	jw := token.NewJW("secret", "user", 15*time.Minute)
+	jw := token.NewJW(jwtKey, nil, "user", 15*time.Minute)
 	if err := jw.Generate(); err != nil {
 		t.Error(err)
 	}
 	oldSess := &auth.Session{
+		Id:      "session",
 		UserId:  "user",
-		Refresh: repository.TestUser["refresh"],
+		Refresh: repository.TestSessions["session"].Refresh,
 		JWT:     jw.Token(),
 	}
 
@@ -99,14 +170,1135 @@ func TestRenew(t *testing.T) {
 	newSess, err := srv.Renew(ctx, oldSess)
 	if err != nil {
 		t.Error(err)
+		t.FailNow()
 	}
 
 	if i := sort.SearchStrings(repository.TestBlacklist,
-		jw.Token()); repository.TestBlacklist[i] != jw.Token() {
+		jw.Id()); i == len(repository.TestBlacklist) || repository.TestBlacklist[i] != jw.Id() {
 		t.Error("JWT not in repository.TestBlacklist")
 	}
 
-	if repository.TestUser["refresh"] != newSess.Refresh {
-		t.Error("new refresh has not been set on repository.TestUser")
+	if newSess.Id != "session" {
+		t.Errorf("renewed session has a different id wanted: session got: %s", newSess.Id)
+	}
+
+	valid, err := srv.IsValidRefresh(ctx, "user", "session", newSess.Refresh)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !valid {
+		t.Error("new refresh has not been set on repository.TestSessions")
+	}
+
+	if repository.TestSessions["session"].Refresh == newSess.Refresh {
+		t.Error("new refresh was stored in plaintext on repository.TestSessions")
+	}
+
+	if _, err = srv.Renew(ctx, oldSess); !errors.Is(err, auth.ErrRefreshReused) {
+		t.Errorf("expected ErrRefreshReused when renewing with old refresh got: %v", err)
+	}
+}
+
+func TestRenewExpiredJWT(t *testing.T) {
+	resetRepo()
+	jw := token.NewJW(jwtKey, nil, "user", -time.Minute)
+	if err := jw.Generate(); err != nil {
+		t.Error(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	newSess, err := srv.Renew(ctx, &auth.Session{
+		Id:      "session",
+		UserId:  "user",
+		Refresh: repository.TestSessions["session"].Refresh,
+		JWT:     jw.Token(),
+	})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.Identify(ctx, newSess.JWT); err != nil {
+		t.Errorf("failed to identify renewed JWT: %s", err)
+	}
+}
+
+func TestDestroySessionExpiredJWT(t *testing.T) {
+	resetRepo()
+	jw := token.NewJW(jwtKey, nil, "user", -time.Minute)
+	if err := jw.Generate(); err != nil {
+		t.Error(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	refresh := repository.TestSessions["session"].Refresh
+	if err := srv.DestroySession(ctx, &auth.Session{
+		Id:      "session",
+		UserId:  "user",
+		Refresh: refresh,
+		JWT:     jw.Token(),
+	}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	valid, err := srv.IsValidRefresh(ctx, "user", "session", refresh)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if valid {
+		t.Error("refresh token is still valid after logging out")
+	}
+
+	if err = srv.DestroySession(ctx, &auth.Session{Id: "session", UserId: "user",
+		Refresh: refresh}); !errors.Is(err, auth.ErrInvalidSession) {
+		t.Errorf("expected ErrInvalidSession for a destroyed session got: %v", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	session, err := srv.Register(ctx, "new_user", "new_password1", true, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if session == nil || session.UserId != "new_user" || session.JWT == "" {
+		t.Errorf("expected a session for new_user got: %+v", session)
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "new_user", "new_password1",
+		auth.Device{}); err != nil {
+		t.Errorf("failed to login as registered user: %s", err)
+	}
+
+	if _, err = srv.Register(ctx, "new_user", "other_password1", false,
+		auth.Device{}); !errors.Is(err, auth.ErrUserExist) {
+		t.Errorf("expected ErrUserExist got: %v", err)
+	}
+
+	if _, err = srv.Register(ctx, "weak_user", "password", false,
+		auth.Device{}); !errors.Is(err, auth.ErrWeakPassword) {
+		t.Errorf("expected ErrWeakPassword got: %v", err)
+	}
+
+	// ids outside the allowed set, including ones colliding with other repository keys
+	for _, userId := range []string{"", "user:user", "user:session", "new user",
+		strings.Repeat("a", 65)} {
+		if _, err = srv.Register(ctx, userId, "new_password1", false,
+			auth.Device{}); !errors.Is(err, auth.ErrInvalidUserId) {
+			t.Errorf("expected ErrInvalidUserId for %q got: %v", userId, err)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	// the session is usually created in the same second as the revocation
+	old, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if err := srv.ChangePassword(ctx, "user", "wrong_password1", "new_password1",
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidChallenge) {
+		t.Errorf("expected ErrInvalidChallenge got: %v", err)
+	}
+
+	if err := srv.ChangePassword(ctx, "user", password, "new_password1",
+		auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(repository.TestSessions) != 0 {
+		t.Error("sessions were not removed from repository.TestSessions")
+	}
+
+	revoked, err := srv.IsRevokedJWT(ctx, old.JWT)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !revoked {
+		t.Error("JWT issued before password change was not revoked")
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidChallenge) {
+		t.Errorf("expected ErrInvalidChallenge for old password got: %v", err)
+	}
+
+	session, err := srv.SessionWithChallenge(ctx, "user", "new_password1", auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	// a jwt issued right after the revocation must still be accepted
+	if revoked, err = srv.IsRevokedJWT(ctx, session.JWT); err != nil || revoked {
+		t.Errorf("JWT issued after password change was revoked: %v", err)
+	}
+
+	if _, err = srv.Identify(ctx, session.JWT); err != nil {
+		t.Errorf("failed to identify JWT issued after password change: %s", err)
+	}
+}
+
+func TestSessionWithChallengeInvalid(t *testing.T) {
+	resetRepo()
+	refresh := repository.TestSessions["session"].Refresh
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.SessionWithChallenge(ctx, "user", "wrong_password1",
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidChallenge) {
+		t.Errorf("expected ErrInvalidChallenge got: %v", err)
+	}
+
+	if len(repository.TestSessions) != 1 || repository.TestSessions["session"].Refresh != refresh {
+		t.Error("failed challenge changed the existing sessions in repository.TestSessions")
+	}
+}
+
+func TestMultipleSessions(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	laptop, err := srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{UserAgent: "laptop", ClientIP: "10.0.0.1"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	phone, err := srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{UserAgent: "phone", ClientIP: "10.0.0.2"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if laptop.Id == phone.Id {
+		t.Error("sessions share the same id")
+	}
+
+	if len(repository.TestSessions) != 3 {
+		t.Errorf("expected 3 sessions in repository.TestSessions got: %d",
+			len(repository.TestSessions))
+	}
+
+	if err = srv.DestroySession(ctx, phone); err != nil {
+		t.Error(err)
+	}
+
+	valid, err := srv.IsValidRefresh(ctx, laptop.UserId, laptop.Id, laptop.Refresh)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !valid {
+		t.Error("destroying one session invalidated another")
+	}
+
+	if sess := repository.TestSessions[laptop.Id]; sess.UserAgent != "laptop" ||
+		sess.ClientIP != "10.0.0.1" {
+		t.Errorf("device was not stored with session got: %+v", sess)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{UserAgent: "phone"}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	sessions, err := srv.ListSessions(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(sessions) != 2 {
+		t.Errorf("expected 2 sessions got: %d", len(sessions))
+	}
+
+	for _, session := range sessions {
+		if session.Refresh != "" || session.JWT != "" {
+			t.Errorf("session: %s was listed with its tokens", session.Id)
+		}
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if err = srv.RevokeSession(ctx, "user", session.Id); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.Authenticate(ctx, session.JWT); !errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected revoked session JWT to be invalid got: %v", err)
+	}
+
+	if _, ok := repository.TestSessions[session.Id]; ok {
+		t.Error("session was not removed from repository.TestSessions")
+	}
+
+	if _, ok := repository.TestSessions["session"]; !ok {
+		t.Error("revoking one session removed another from repository.TestSessions")
+	}
+
+	if err = srv.RevokeSession(ctx, "user",
+		session.Id); !errors.Is(err, auth.ErrSessionNotExist) {
+		t.Errorf("expected ErrSessionNotExist got: %v", err)
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if err = srv.RevokeAllSessions(ctx, "user"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(repository.TestSessions) != 0 {
+		t.Error("sessions were not removed from repository.TestSessions")
+	}
+
+	// the session jwt may have been issued in the same second as the revocation
+	if len(repository.TestBlacklist) != 1 {
+		t.Errorf("expected the session JWT to be blacklisted got: %v", repository.TestBlacklist)
+	}
+
+	revoked, err := srv.IsRevokedJWT(ctx, session.JWT)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !revoked {
+		t.Error("JWT issued before every session was revoked should be revoked")
+	}
+}
+
+func TestRevokeUnknownUser(t *testing.T) {
+	resetRepo()
+	// a record without a salt hasn't been registered
+	repository.TestUsers["ghost"] = map[string]string{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if err := srv.RevokeAllSessions(ctx, "ghost"); !errors.Is(err, auth.ErrUserNotExist) {
+		t.Errorf("expected ErrUserNotExist got: %v", err)
+	}
+
+	if err := srv.RemoveRole(ctx, "ghost", "admin"); !errors.Is(err, auth.ErrUserNotExist) {
+		t.Errorf("expected ErrUserNotExist got: %v", err)
+	}
+
+	if _, ok := repository.TestUsers["ghost"]["revoked"]; ok {
+		t.Error("revoking an unknown user set a revocation on its record")
+	}
+}
+
+func TestRenewReuse(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	first, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	second, err := srv.Renew(ctx, first)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if repository.TestSessions[first.Id].Parent == "" {
+		t.Error("rotated session does not record its parent refresh token")
+	}
+
+	stolen := &auth.Session{Id: first.Id, UserId: first.UserId, Refresh: first.Refresh,
+		Device: auth.Device{ClientIP: "10.0.0.9"}}
+	if _, err = srv.Renew(ctx, stolen); !errors.Is(err, auth.ErrRefreshReused) {
+		t.Errorf("expected ErrRefreshReused got: %v", err)
+	}
+
+	if _, ok := repository.TestSessions[first.Id]; ok {
+		t.Error("session was not revoked after its refresh token was reused")
+	}
+
+	if _, err = srv.Authenticate(ctx, second.JWT); !errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected JWT of revoked session to be invalid got: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Type != auth.EventRefreshReuse ||
+		events[0].Device.ClientIP != "10.0.0.9" {
+		t.Errorf("expected a refresh reuse security event got: %+v", events)
+	}
+
+	if _, ok := repository.TestSessions["session"]; !ok {
+		t.Error("revoking a reused session removed another session")
+	}
+}
+
+func TestRefreshHashedAtRest(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	mac := hmac.New(sha256.New, []byte("refresh_secret"))
+	mac.Write([]byte(session.Refresh))
+	if stored := repository.TestSessions[session.Id].Refresh; stored !=
+		"hmac-sha256:"+hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("refresh was not stored as a keyed hash got: %s", stored)
+	}
+
+	// the fixture session was stored before refresh tokens were hashed
+	legacy := repository.TestSessions["session"].Refresh
+	valid, err := srv.IsValidRefresh(ctx, "user", "session", legacy)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !valid {
+		t.Error("plaintext refresh token stored before hashing is no longer valid")
+	}
+
+	if valid, _ = srv.IsValidRefresh(ctx, "user", session.Id,
+		repository.TestSessions[session.Id].Refresh); valid {
+		t.Error("stored hash was accepted as a refresh token")
+	}
+}
+
+func TestPreviousSigningKey(t *testing.T) {
+	resetRepo()
+
+	jw := token.NewJW(prevKey, nil, "user", 15*time.Minute)
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := srv.IsValidJWT(jw.Token()); !ok || err != nil {
+		t.Errorf("jwt signed with a previous key should be valid got: %v", err)
+	}
+
+	jw = token.NewJW(token.NewHMACKey("unknown_secret"), nil, "user", 15*time.Minute)
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, _ := srv.IsValidJWT(jw.Token()); ok {
+		t.Error("jwt signed with an unknown key should be invalid")
+	}
+
+	if len(srv.KeySet().Keys) != 0 {
+		t.Errorf("HMAC keys should not be published got: %d keys", len(srv.KeySet().Keys))
+	}
+}
+
+func TestRoles(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if err := srv.AssignRole(ctx, "user", "owner"); !errors.Is(err, auth.ErrRoleNotExist) {
+		t.Errorf("expected ErrRoleNotExist got: %v", err)
+	}
+
+	for _, role := range []string{"user", "admin"} {
+		if err := srv.AssignRole(ctx, "user", role); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	jw, err := token.NewJWFromExisting(jwtKey, nil, session.JWT)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	grants := jw.Grants()
+	if grants.PermLevel != 100 {
+		t.Errorf("perm level does not match wanted: 100 got: %d", grants.PermLevel)
+	}
+
+	if strings.Join(grants.Roles, ",") != "admin,user" {
+		t.Errorf("roles do not match wanted: [admin user] got: %v", grants.Roles)
+	}
+
+	if strings.Join(grants.Permissions, ",") != "sessions:read,sessions:revoke,users:manage" {
+		t.Errorf("permissions do not match got: %v", grants.Permissions)
+	}
+
+	if !jw.HasPermission("users:manage") {
+		t.Error("JWT should have the users:manage permission")
+	}
+
+	identity, err := srv.Identify(ctx, session.JWT)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if identity.UserId != "user" || strings.Join(identity.Roles, ",") != "admin,user" {
+		t.Errorf("unexpected identity: %+v", identity)
+	}
+
+	if err = srv.RemoveRole(ctx, "user", "admin"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	revoked, err := srv.IsRevokedJWT(ctx, session.JWT)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !revoked {
+		t.Error("JWT issued before a role was removed should be revoked")
+	}
+
+	remaining, err := srv.Grants(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if remaining.PermLevel != 10 || strings.Join(remaining.Roles, ",") != "user" {
+		t.Errorf("unexpected grants after removing role: %+v", remaining)
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	session, err := srv.SessionWithChallenge(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	for _, test := range []struct {
+		jwt, resource string
+		allowed       bool
+	}{
+		{session.JWT, "users/user/sessions", true},
+		{session.JWT, "users/other/sessions", false},
+		{"not.a.jwt", "users/user/sessions", false},
+	} {
+		decision, err := srv.Authorize(ctx, test.jwt, "sessions:read", test.resource)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		if decision.Allowed != test.allowed {
+			t.Errorf("%s: wanted allowed: %t got: %t: %s", test.resource, test.allowed,
+				decision.Allowed, decision.Reason)
+		}
+	}
+
+	if err = srv.RevokeAllSessions(ctx, "user"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	decision, err := srv.Authorize(ctx, session.JWT, "sessions:read", "users/user/sessions")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if decision.Allowed || decision.Reason != "token has been revoked" {
+		t.Errorf("revoked token should be denied got: %+v", decision)
+	}
+}
+
+// totpCode will generate the totp code of a secret for the time step offset from now
+func totpCode(t *testing.T, secret string, offset int64) string {
+	code, err := token.TOTPCode(secret, token.TOTPStep(time.Now())+offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return code
+}
+
+func TestMFA(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.ConfirmTOTP(ctx, "user", "000000",
+		auth.Device{}); !errors.Is(err, auth.ErrMFANotEnrolled) {
+		t.Errorf("expected ErrMFANotEnrolled got: %v", err)
+	}
+
+	enrollment, err := srv.EnrollTOTP(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(enrollment.URI, "otpauth://totp/auth:user?") {
+		t.Errorf("unexpected totp uri: %s", enrollment.URI)
+	}
+
+	if strings.Contains(repository.TestMFA["user"].Pending, enrollment.Secret) {
+		t.Error("totp secret should be encrypted at rest")
+	}
+
+	// mfa isn't required until the secret has been confirmed
+	if _, err = srv.SessionWithChallenge(ctx, "user", password, auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	recoveryCodes, err := srv.ConfirmTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, -1), auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(recoveryCodes) != 10 || len(repository.TestRecoveryCodes["user"]) != 10 {
+		t.Errorf("expected 10 recovery codes got: %d", len(recoveryCodes))
+	}
+
+	for _, code := range recoveryCodes {
+		for _, hash := range repository.TestRecoveryCodes["user"] {
+			if strings.Contains(hash, code) {
+				t.Error("recovery codes should be hashed at rest")
+			}
+		}
+	}
+
+	if _, err = srv.EnrollTOTP(ctx, "user"); !errors.Is(err, auth.ErrMFAEnabled) {
+		t.Errorf("expected ErrMFAEnabled got: %v", err)
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{}); !errors.Is(err, auth.ErrMFARequired) {
+		t.Errorf("expected ErrMFARequired got: %v", err)
+	}
+
+	session, mfaToken, err := srv.Login(ctx, "user", password, auth.Device{UserAgent: "test"})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if session != nil || mfaToken == "" {
+		t.Fatal("login should return an mfa token instead of a session")
+	}
+
+	// the code used to confirm the secret can't be replayed
+	if _, err = srv.VerifyMFA(ctx, mfaToken, totpCode(t, enrollment.Secret, -1),
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode for a replayed code got: %v", err)
+	}
+
+	session, err = srv.VerifyMFA(ctx, mfaToken, totpCode(t, enrollment.Secret, 0),
+		auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if session.JWT == "" || session.UserAgent != "test" {
+		t.Errorf("unexpected session from mfa verification: %+v", session)
+	}
+
+	if _, err = srv.VerifyMFA(ctx, mfaToken, totpCode(t, enrollment.Secret, 1),
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidMFAChallenge) {
+		t.Errorf("expected ErrInvalidMFAChallenge for a used challenge got: %v", err)
+	}
+
+	if err = srv.DisableTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, 1), auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "user", password, auth.Device{}); err != nil {
+		t.Errorf("mfa should not be required once disabled got: %v", err)
+	}
+}
+
+func TestMFAAttempts(t *testing.T) {
+	resetRepo()
+	defer resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	enrollment, err := srv.EnrollTOTP(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.ConfirmTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, -1), auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	_, mfaToken, err := srv.Login(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	wrong := "000000"
+	if wrong == totpCode(t, enrollment.Secret, 0) {
+		wrong = "111111"
+	}
+
+	// clear the users failures so only the attempts of the challenge are limited
+	for i := 0; i < 5; i++ {
+		if err = srv.ClearLockout(ctx, "user", ""); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if _, err = srv.VerifyMFA(ctx, mfaToken, wrong, auth.Device{}); !errors.Is(err,
+			auth.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode got: %v", err)
+		}
+	}
+
+	if _, err = srv.VerifyMFA(ctx, mfaToken, totpCode(t, enrollment.Secret, 0),
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidMFAChallenge) {
+		t.Errorf("expected challenge to be removed after too many attempts got: %v", err)
+	}
+
+	if err = srv.ClearLockout(ctx, "user", ""); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	// invalid codes count towards the lockout of the user across challenges
+	dev := auth.Device{ClientIP: "10.0.0.1"}
+	for i := 0; i < 5; i++ {
+		// simulate the back-off of the user and client ip passing
+		for _, subject := range []string{"user:user", "ip:" + dev.ClientIP} {
+			failures := repository.TestFailures[subject]
+			for j := range failures {
+				failures[j] = failures[j].Add(-5 * time.Minute)
+			}
+		}
+
+		// a correct password doesn't forget the invalid codes
+		if _, mfaToken, err = srv.Login(ctx, "user", password, dev); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if _, err = srv.VerifyMFA(ctx, mfaToken, wrong, dev); !errors.Is(err,
+			auth.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode got: %v", err)
+		}
+	}
+
+	status, err := srv.Lockout(ctx, "user", dev.ClientIP)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if status.LockedUntil.IsZero() || status.IPFailures != 5 {
+		t.Errorf("expected invalid codes to lock the account got: %+v", status)
+	}
+}
+
+func TestMFAManagementLockout(t *testing.T) {
+	resetRepo()
+	defer resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	enrollment, err := srv.EnrollTOTP(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	wrong := "000000"
+	if wrong == totpCode(t, enrollment.Secret, 0) {
+		wrong = "111111"
+	}
+
+	dev := auth.Device{ClientIP: "10.0.0.2"}
+	for i := 0; i < 3; i++ {
+		if _, err = srv.ConfirmTOTP(ctx, "user", wrong, dev); !errors.Is(err,
+			auth.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode got: %v", err)
+		}
+	}
+
+	if _, err = srv.ConfirmTOTP(ctx, "user", totpCode(t, enrollment.Secret, -1),
+		dev); !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Errorf("expected ErrTooManyAttempts after invalid codes got: %v", err)
+	}
+
+	if err = srv.ClearLockout(ctx, "user", dev.ClientIP); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	recoveryCodes, err := srv.ConfirmTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, -1), dev)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	for i := 0; i < 5; i++ {
+		// simulate the back-off of the user and client ip passing
+		for _, subject := range []string{"user:user", "ip:" + dev.ClientIP} {
+			failures := repository.TestFailures[subject]
+			for j := range failures {
+				failures[j] = failures[j].Add(-5 * time.Minute)
+			}
+		}
+
+		if i%2 == 0 {
+			err = srv.DisableTOTP(ctx, "user", "aaaa-bbbb", dev)
+		} else {
+			_, err = srv.RegenerateRecoveryCodes(ctx, "user", wrong, dev)
+		}
+
+		if !errors.Is(err, auth.ErrInvalidMFACode) {
+			t.Errorf("expected ErrInvalidMFACode got: %v", err)
+		}
+	}
+
+	if err = srv.DisableTOTP(ctx, "user", recoveryCodes[0],
+		dev); !errors.Is(err, auth.ErrAccountLocked) {
+		t.Errorf("expected ErrAccountLocked after invalid codes got: %v", err)
+	}
+
+	status, err := srv.MFAStatus(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if !status.TOTPEnabled || status.RecoveryCodes != 10 {
+		t.Errorf("expected mfa to be unchanged while locked got: %+v", status)
+	}
+}
+
+func TestMFAChallengeRedeemedOnce(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	enrollment, err := srv.EnrollTOTP(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	recoveryCodes, err := srv.ConfirmTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, -1), auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	_, mfaToken, err := srv.Login(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	var (
+		wg       sync.WaitGroup
+		sessions int32
+	)
+	for _, code := range recoveryCodes[:2] {
+		wg.Add(1)
+		go func(code string) {
+			defer wg.Done()
+			if _, err := srv.VerifyMFA(ctx, mfaToken, code, auth.Device{}); err == nil {
+				atomic.AddInt32(&sessions, 1)
+			}
+		}(code)
+	}
+	wg.Wait()
+
+	if sessions != 1 {
+		t.Errorf("expected a challenge to create a single session got: %d", sessions)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	if _, err := srv.RegenerateRecoveryCodes(ctx, "user", "000000",
+		auth.Device{}); !errors.Is(err, auth.ErrMFANotEnabled) {
+		t.Errorf("expected ErrMFANotEnabled got: %v", err)
+	}
+
+	enrollment, err := srv.EnrollTOTP(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	recoveryCodes, err := srv.ConfirmTOTP(ctx, "user",
+		totpCode(t, enrollment.Secret, -1), auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	_, mfaToken, err := srv.Login(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	// codes are accepted regardless of case and separators
+	if _, err = srv.VerifyMFA(ctx, mfaToken, strings.ToUpper(strings.Replace(recoveryCodes[0],
+		"-", "", 1)), auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	_, mfaToken, err = srv.Login(ctx, "user", password, auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.VerifyMFA(ctx, mfaToken, recoveryCodes[0],
+		auth.Device{}); !errors.Is(err,
+		auth.ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode for a used recovery code got: %v", err)
+	}
+
+	status, err := srv.MFAStatus(ctx, "user")
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if !status.TOTPEnabled || status.RecoveryCodes != 9 {
+		t.Errorf("unexpected mfa status: %+v", status)
+	}
+
+	regenerated, err := srv.RegenerateRecoveryCodes(ctx, "user", recoveryCodes[1],
+		auth.Device{})
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(regenerated) != 10 {
+		t.Errorf("expected 10 recovery codes got: %d", len(regenerated))
+	}
+
+	if err = srv.DisableTOTP(ctx, "user", recoveryCodes[2],
+		auth.Device{}); !errors.Is(err, auth.ErrInvalidMFACode) {
+		t.Errorf("expected ErrInvalidMFACode for a replaced recovery code got: %v", err)
+	}
+
+	if err = srv.DisableTOTP(ctx, "user", regenerated[0],
+		auth.Device{}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if status, err = srv.MFAStatus(ctx, "user"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if status.TOTPEnabled || status.RecoveryCodes != 0 {
+		t.Errorf("mfa should be removed once disabled got: %+v", status)
+	}
+}
+
+func TestLockoutDelay(t *testing.T) {
+	policy := auth.LockoutPolicy{
+		BackoffThreshold: 3,
+		BaseDelay:        time.Second,
+		MaxDelay:         5 * time.Second,
+	}
+
+	for failures, want := range []time.Duration{0, 0, 0, time.Second, 2 * time.Second,
+		4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		if delay := policy.Delay(failures); delay != want {
+			t.Errorf("delay after %d failures does not match wanted: %s got: %s", failures,
+				want, delay)
+		}
+	}
+
+	policy.MaxDelay = 0
+	if delay := policy.Delay(1000); delay <= 0 {
+		t.Errorf("delay should not overflow without a maximum got: %s", delay)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	resetRepo()
+	defer resetRepo()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Millisecond)
+	defer cancel()
+
+	dev := auth.Device{ClientIP: "10.0.0.1"}
+	for i := 0; i < 3; i++ {
+		if _, err := srv.SessionWithChallenge(ctx, "user", "wrong_password1",
+			dev); !errors.Is(err, auth.ErrInvalidChallenge) {
+			t.Errorf("expected ErrInvalidChallenge got: %v", err)
+		}
+	}
+
+	// back-off applies to the user and client ip even with the right password
+	if _, err := srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{}); !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Errorf("expected ErrTooManyAttempts for the user got: %v", err)
+	}
+
+	if _, err := srv.SessionWithChallenge(ctx, "other", password,
+		dev); !errors.Is(err, auth.ErrTooManyAttempts) {
+		t.Errorf("expected ErrTooManyAttempts for the client ip got: %v", err)
+	}
+
+	status, err := srv.Lockout(ctx, "user", dev.ClientIP)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if status.UserFailures != 3 || status.IPFailures != 3 || status.RetryAt.IsZero() ||
+		!status.LockedUntil.IsZero() {
+		t.Errorf("unexpected lockout status: %+v", status)
+	}
+
+	// simulate the back-off passing until the user crosses the lockout threshold
+	for i := 0; i < 2; i++ {
+		failures := repository.TestFailures["user:user"]
+		for j := range failures {
+			failures[j] = failures[j].Add(-5 * time.Minute)
+		}
+
+		if err := srv.ClearLockout(ctx, "other", dev.ClientIP); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if _, err = srv.SessionWithChallenge(ctx, "user", "wrong_password1",
+			dev); !errors.Is(err, auth.ErrInvalidChallenge) {
+			t.Errorf("expected ErrInvalidChallenge got: %v", err)
+		}
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "user", password,
+		auth.Device{}); !errors.Is(err, auth.ErrAccountLocked) {
+		t.Errorf("expected ErrAccountLocked got: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Type != auth.EventAccountLocked ||
+		events[0].Device.ClientIP != dev.ClientIP {
+		t.Errorf("expected an account locked event got: %v", events)
+	}
+
+	if status, err = srv.Lockout(ctx, "user", ""); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if status.LockedUntil.IsZero() {
+		t.Errorf("account should be locked: %+v", status)
+	}
+
+	if err = srv.ClearLockout(ctx, "user", dev.ClientIP); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = srv.SessionWithChallenge(ctx, "user", password, dev); err != nil {
+		t.Errorf("lockout should be cleared got: %v", err)
 	}
 }
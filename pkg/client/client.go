@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNoSession   = errors.New("client does not have a session")
+	ErrMFARequired = errors.New("multi-factor authentication is required")
+	ErrNoMFA       = errors.New("client does not have a pending mfa challenge")
+)
+
+// Options of a client
+type Options struct {
+	// RefreshBefore is how long before the jwt expires the session is refreshed
+	RefreshBefore time.Duration
+	// AllowInsecure lets per rpc credentials be sent over connections without transport
+	// security, which should only be used for testing
+	AllowInsecure bool
+}
+
+// Client logs in to the authentication service and keeps its session fresh. A client is safe
+// to use from multiple goroutines
+type Client struct {
+	ac  proto.AuthenticationClient
+	opt *Options
+
+	// lock is held while the session is read or changed, it's a channel so waiting for it
+	// can be cancelled
+	lock      chan struct{}
+	session   *proto.Session
+	expiresAt time.Time
+	mfaToken  string
+}
+
+// NewClient will create a client calling the authentication service through conn
+func NewClient(conn grpc.ClientConnInterface, opt *Options) *Client {
+	return NewClientWith(proto.NewAuthenticationClient(conn), opt)
+}
+
+// NewClientWith will create a client calling the authentication service through ac
+func NewClientWith(ac proto.AuthenticationClient, opt *Options) *Client {
+	if opt == nil {
+		opt = &Options{}
+	}
+	if opt.RefreshBefore == 0 {
+		opt.RefreshBefore = time.Minute
+	}
+
+	return &Client{ac: ac, opt: opt, lock: make(chan struct{}, 1)}
+}
+
+// acquire will wait for the lock, returns an error if the context is done first
+func (c *Client) acquire(ctx context.Context) error {
+	select {
+	case c.lock <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Client) release() {
+	<-c.lock
+}
+
+// jwtExpiration will read the exp claim of a jwt without verifying it, returns the zero time
+// when the claim can't be read
+func jwtExpiration(jwt string) time.Time {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	claims := struct {
+		Exp float64 `json:"exp"`
+	}{}
+	if err = json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(claims.Exp), 0)
+}
+
+// setSession will cache a session along with when its jwt expires
+func (c *Client) setSession(session *proto.Session) {
+	c.session = session
+	c.expiresAt = jwtExpiration(session.GetJwt())
+	c.mfaToken = ""
+}
+
+// Login will create a session for a user. ErrMFARequired is returned when the user has a
+// second factor enabled, the session is then created by VerifyMFA
+func (c *Client) Login(ctx context.Context, username, password string) error {
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	session, err := c.ac.Login(ctx, &proto.Credentials{Username: username, Password: password})
+	if err != nil {
+		return err
+	}
+
+	if session.GetMfaRequired() {
+		c.session = nil
+		c.mfaToken = session.GetMfaToken()
+		return ErrMFARequired
+	}
+
+	c.setSession(session)
+
+	return nil
+}
+
+// VerifyMFA will create a session with a totp or recovery code after Login returned
+// ErrMFARequired
+func (c *Client) VerifyMFA(ctx context.Context, code string) error {
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	if c.mfaToken == "" {
+		return ErrNoMFA
+	}
+
+	session, err := c.ac.VerifyMFA(ctx, &proto.MFAVerification{MfaToken: c.mfaToken,
+		Code: code})
+	if err != nil {
+		return err
+	}
+
+	c.setSession(session)
+
+	return nil
+}
+
+// refresh will renew the session, the session is dropped when the service no longer accepts
+// it. The lock must be held
+func (c *Client) refresh(ctx context.Context) error {
+	if c.session == nil {
+		return ErrNoSession
+	}
+
+	session, err := c.ac.Refresh(ctx, c.session)
+	if err != nil {
+		if status.Code(err) == codes.Unauthenticated {
+			c.session = nil
+			return fmt.Errorf("%s: %w", err, ErrNoSession)
+		}
+		return err
+	}
+
+	c.setSession(session)
+
+	return nil
+}
+
+// Refresh will renew the session now
+func (c *Client) Refresh(ctx context.Context) error {
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	return c.refresh(ctx)
+}
+
+// Session will get a copy of the current session, returns nil when there isn't one
+func (c *Client) Session() *proto.Session {
+	c.lock <- struct{}{}
+	defer c.release()
+
+	if c.session == nil {
+		return nil
+	}
+
+	session := *c.session
+	return &session
+}
+
+// Token will get the jwt of the session, the session is refreshed first when the jwt expires
+// within the refresh window. Concurrent calls wait for a single refresh
+func (c *Client) Token(ctx context.Context) (string, error) {
+	if err := c.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer c.release()
+
+	if c.session == nil {
+		return "", ErrNoSession
+	}
+
+	if !c.expiresAt.IsZero() && time.Until(c.expiresAt) < c.opt.RefreshBefore {
+		if err := c.refresh(ctx); err != nil {
+			return "", fmt.Errorf("failed to refresh session: %w", err)
+		}
+	}
+
+	return c.session.GetJwt(), nil
+}
+
+// Logout will destroy the session
+func (c *Client) Logout(ctx context.Context) error {
+	if err := c.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.release()
+
+	if c.session == nil {
+		return ErrNoSession
+	}
+
+	if _, err := c.ac.Logout(ctx, c.session); err != nil {
+		return err
+	}
+
+	c.session = nil
+
+	return nil
+}
+
+// Credentials will get per rpc credentials attaching the jwt of the session to calls as a
+// bearer token, the session is refreshed as needed
+func (c *Client) Credentials() credentials.PerRPCCredentials {
+	return &perRPCCredentials{c}
+}
+
+type perRPCCredentials struct {
+	c *Client
+}
+
+func (pc *perRPCCredentials) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+	jwt, err := pc.c.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"authorization": "Bearer " + jwt}, nil
+}
+
+func (pc *perRPCCredentials) RequireTransportSecurity() bool {
+	return !pc.c.opt.AllowInsecure
+}
@@ -0,0 +1,175 @@
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/client"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newJWT will create an unsigned jwt expiring at exp
+func newJWT(exp time.Time) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(fmt.Sprintf(`{"sub":"user","exp":%d}`, exp.Unix()))) +
+		".signature"
+}
+
+type authClient struct {
+	proto.AuthenticationClient
+	refreshes int32
+	// lifetime of issued jwts
+	lifetime time.Duration
+	mfa      bool
+	revoked  bool
+}
+
+func (ac *authClient) Login(ctx context.Context, in *proto.Credentials,
+	opts ...grpc.CallOption) (*proto.Session, error) {
+	if ac.mfa {
+		return &proto.Session{UserId: in.GetUsername(), MfaRequired: true,
+			MfaToken: "mfa_token"}, nil
+	}
+
+	return &proto.Session{UserId: in.GetUsername(), SessionId: "session",
+		Jwt: newJWT(time.Now().Add(ac.lifetime)), RefreshToken: "refresh_0"}, nil
+}
+
+func (ac *authClient) VerifyMFA(ctx context.Context, in *proto.MFAVerification,
+	opts ...grpc.CallOption) (*proto.Session, error) {
+	if in.GetMfaToken() != "mfa_token" || in.GetCode() != "123456" {
+		return nil, status.Error(codes.PermissionDenied, "mfa code is not valid")
+	}
+
+	return &proto.Session{UserId: "user", SessionId: "session",
+		Jwt: newJWT(time.Now().Add(ac.lifetime)), RefreshToken: "refresh_0"}, nil
+}
+
+func (ac *authClient) Refresh(ctx context.Context, in *proto.Session,
+	opts ...grpc.CallOption) (*proto.Session, error) {
+	if ac.revoked {
+		return nil, status.Error(codes.Unauthenticated, "session is not valid")
+	}
+
+	n := atomic.AddInt32(&ac.refreshes, 1)
+	// give concurrent callers a chance to pile up
+	time.Sleep(10 * time.Millisecond)
+
+	return &proto.Session{
+		UserId:       in.GetUserId(),
+		SessionId:    in.GetSessionId(),
+		Jwt:          newJWT(time.Now().Add(time.Hour)),
+		RefreshToken: fmt.Sprintf("refresh_%d", n),
+	}, nil
+}
+
+func (ac *authClient) Logout(ctx context.Context, in *proto.Session,
+	opts ...grpc.CallOption) (*proto.LogoutStatus, error) {
+	return &proto.LogoutStatus{UserId: in.GetUserId(), Success: true}, nil
+}
+
+func TestToken(t *testing.T) {
+	ctx := context.Background()
+	ac := &authClient{lifetime: 30 * time.Second}
+	c := client.NewClientWith(ac, &client.Options{RefreshBefore: time.Minute})
+
+	if _, err := c.Token(ctx); !errors.Is(err, client.ErrNoSession) {
+		t.Errorf("expected ErrNoSession got: %v", err)
+	}
+
+	if err := c.Login(ctx, "user", "123password"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the jwt expires within the refresh window so every caller should wait on one refresh
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.Token(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if refreshes := atomic.LoadInt32(&ac.refreshes); refreshes != 1 {
+		t.Errorf("expected a single refresh got: %d", refreshes)
+	}
+
+	if session := c.Session(); session.GetRefreshToken() != "refresh_1" {
+		t.Errorf("expected refreshed session got: %v", session)
+	}
+
+	md, err := c.Credentials().GetRequestMetadata(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if md["authorization"] != "Bearer "+c.Session().GetJwt() {
+		t.Errorf("unexpected request metadata: %v", md)
+	}
+
+	if !c.Credentials().RequireTransportSecurity() {
+		t.Error("credentials should require transport security by default")
+	}
+
+	if err = c.Logout(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Session() != nil {
+		t.Error("expected session to be dropped after logging out")
+	}
+}
+
+func TestRevokedSession(t *testing.T) {
+	ctx := context.Background()
+	ac := &authClient{lifetime: time.Second}
+	c := client.NewClientWith(ac, nil)
+
+	if err := c.Login(ctx, "user", "123password"); err != nil {
+		t.Fatal(err)
+	}
+
+	ac.revoked = true
+	if _, err := c.Token(ctx); !errors.Is(err, client.ErrNoSession) {
+		t.Errorf("expected ErrNoSession got: %v", err)
+	}
+
+	if c.Session() != nil {
+		t.Error("expected revoked session to be dropped")
+	}
+}
+
+func TestMFA(t *testing.T) {
+	ctx := context.Background()
+	c := client.NewClientWith(&authClient{lifetime: time.Hour, mfa: true}, nil)
+
+	if err := c.VerifyMFA(ctx, "123456"); !errors.Is(err, client.ErrNoMFA) {
+		t.Errorf("expected ErrNoMFA got: %v", err)
+	}
+
+	if err := c.Login(ctx, "user", "123password"); !errors.Is(err, client.ErrMFARequired) {
+		t.Fatalf("expected ErrMFARequired got: %v", err)
+	}
+
+	if err := c.VerifyMFA(ctx, "123456"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Token(ctx); err != nil {
+		t.Error(err)
+	}
+}
@@ -5,35 +5,57 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	gohttp "net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
+	goredis "github.com/go-redis/redis"
 	"github.com/joshturge-io/auth/pkg/auth"
 	"github.com/joshturge-io/auth/pkg/grpc"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
 	"github.com/joshturge-io/auth/pkg/grpc/service"
+	"github.com/joshturge-io/auth/pkg/http"
+	"github.com/joshturge-io/auth/pkg/policy"
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+	redisLimiter "github.com/joshturge-io/auth/pkg/ratelimit/redis"
 	"github.com/joshturge-io/auth/pkg/repository"
 	"github.com/joshturge-io/auth/pkg/repository/redis"
+	"github.com/joshturge-io/auth/pkg/token"
 	"golang.org/x/sync/errgroup"
+	gogrpc "google.golang.org/grpc"
 )
 
 // App holds all the repository and gRPC server methods
 type App struct {
-	repo repository.Repository
-	srv  *grpc.Server
-	lg   *log.Logger
+	repo    repository.Repository
+	srv     *grpc.Server
+	httpSrv *http.Server
+	lg      *log.Logger
+	secLg   *log.Logger
+
+	// redis client of the shared rate limiter, nil when limits are kept in memory
+	limitClient *goredis.Client
 }
 
 // Initialise the repository and create the gRPC server
 func (a *App) Initialise(configPath string) (err error) {
 	a.lg = log.New(os.Stdout, "[INFO] ", log.Ltime|log.Ldate)
+	a.secLg = log.New(os.Stdout, "[SECURITY] ", log.Ltime|log.Ldate)
 
 	a.lg.Println("Reading configuration file")
 
 	repoPswd := os.Getenv("REPOS_PSWD")
+	adminSecret := os.Getenv("ADMIN_SECRET")
 	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		return errors.New("environment variable JWT_SECRET not set")
+	refreshSecret := os.Getenv("REFRESH_SECRET")
+	if refreshSecret == "" && jwtSecret != "" {
+		a.lg.Println("WARNING: REFRESH_SECRET not set, using JWT_SECRET to hash refresh tokens")
+		refreshSecret = jwtSecret
+	}
+	if refreshSecret == "" {
+		return errors.New("environment variable REFRESH_SECRET not set")
 	}
 
 	config, err := ParseConfig(configPath)
@@ -41,6 +63,27 @@ func (a *App) Initialise(configPath string) (err error) {
 		return fmt.Errorf("unable to read configuration: %w", err)
 	}
 
+	a.lg.Printf("Loading %s jwt signing key\n", config.Token.Jwt.Algorithm)
+
+	var jwtKey *token.Key
+	if config.Token.Jwt.Algorithm == token.AlgHS256 {
+		if jwtSecret == "" {
+			return errors.New("environment variable JWT_SECRET not set")
+		}
+		jwtKey = token.NewHMACKey(jwtSecret)
+	} else {
+		jwtKey, err = token.LoadPrivateKey(config.Token.Jwt.Algorithm,
+			configFilePath(configPath, config.Token.Jwt.PrivateKey))
+		if err != nil {
+			return fmt.Errorf("failed to load jwt signing key: %w", err)
+		}
+	}
+
+	keyring, err := loadKeyring(jwtKey, configPath, config.Token.Jwt.PreviousKeys)
+	if err != nil {
+		return err
+	}
+
 	a.lg.Println("Creating connection to database")
 
 	if os.Getenv("TEST_REPO") != "" {
@@ -60,27 +103,206 @@ func (a *App) Initialise(configPath string) (err error) {
 		RefreshTokenLength:     config.Token.Refresh.Length,
 		JWTokenExpiration:      time.Duration(config.Token.Jwt.Expiration) * time.Minute,
 		RefreshTokenExpiration: time.Duration(config.Token.Refresh.Expiration) * time.Hour,
+		RefreshSecret:          refreshSecret,
 		SaltLength:             config.Cipher.SaltLength,
+		JWTOptions: &token.Options{
+			Issuer:   config.Token.Jwt.Issuer,
+			Audience: config.Token.Jwt.Audience,
+			Leeway:   time.Duration(config.Token.Jwt.Leeway) * time.Second,
+		},
+		PasswordPolicy: auth.PasswordPolicy{
+			MinLength:     config.Password.MinLength,
+			MaxLength:     config.Password.MaxLength,
+			RequireUpper:  config.Password.RequireUpper,
+			RequireLower:  config.Password.RequireLower,
+			RequireDigit:  config.Password.RequireDigit,
+			RequireSymbol: config.Password.RequireSymbol,
+		},
+		LockoutPolicy: auth.LockoutPolicy{
+			Window:           time.Duration(config.Lockout.Window) * time.Minute,
+			BackoffThreshold: config.Lockout.BackoffThreshold,
+			BaseDelay:        time.Duration(config.Lockout.BaseDelay) * time.Second,
+			MaxDelay:         time.Duration(config.Lockout.MaxDelay) * time.Second,
+			LockoutThreshold: config.Lockout.Threshold,
+			LockoutDuration:  time.Duration(config.Lockout.Duration) * time.Minute,
+		},
+		EventHandler:           a.logSecurityEvent,
+		Roles:                  make(map[string]auth.Role, len(config.Roles)),
+		MFAIssuer:              config.MFA.Issuer,
+		MFAChallengeExpiration: time.Duration(config.MFA.ChallengeExpiration) * time.Minute,
+	}
+
+	// a negative window turns off tracking failed attempts
+	if opt.LockoutPolicy.Window < 0 {
+		opt.LockoutPolicy.Window = 0
 	}
 
-	a.srv, err = grpc.NewServer(config.Address,
-		service.NewGRPCAuthService(auth.NewService(jwtSecret, a.repo, config.Cipher.Keys, opt),
-			a.lg))
+	for name, role := range config.Roles {
+		opt.Roles[name] = auth.Role{Level: role.Level, Permissions: role.Permissions}
+	}
+
+	policyPath := configFilePath(configPath, config.Policy)
+	opt.Policy, err = policy.Load(policyPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to load policy: %w", err)
+		}
+		a.lg.Printf("WARNING: policy %s not found, every action will be denied\n", policyPath)
+	}
+
+	authSvc := auth.NewService(keyring, a.repo, config.Cipher.Keys, opt)
+	authGRPC := service.NewGRPCAuthService(authSvc, a.lg)
+	services := []proto.Service{authGRPC}
+
+	if adminSecret != "" {
+		services = append(services, service.NewGRPCAdminService(authSvc, adminSecret, a.lg))
+	} else {
+		a.lg.Println("WARNING: ADMIN_SECRET not set, admin service is disabled")
+	}
+
+	if config.ExtAuthz.Enabled {
+		a.lg.Println("Serving envoy external authorization service")
+		services = append(services, service.NewGRPCExtAuthzService(authSvc,
+			config.ExtAuthz.CookieName, a.lg))
+	}
+
+	limiter := ratelimit.NewMemoryLimiter()
+	if config.RateLimit.Redis && os.Getenv("TEST_REPO") == "" {
+		a.lg.Println("Sharing rate limits through redis")
+		a.limitClient = goredis.NewClient(&goredis.Options{
+			Addr:     config.Repo.Address,
+			Password: repoPswd,
+			DB:       0,
+		})
+		limiter = redisLimiter.NewRedisLimiter(a.limitClient)
+	}
+
+	limits := grpc.RateLimits{
+		Default: ratelimit.Limit{Rate: config.RateLimit.Rate, Burst: config.RateLimit.Burst},
+		Methods: make(map[string]ratelimit.Limit, len(config.RateLimit.Methods)),
+	}
+	for name, limit := range config.RateLimit.Methods {
+		limits.Methods[name] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+
+	interceptors := []gogrpc.UnaryServerInterceptor{}
+	serverOpts := []gogrpc.ServerOption{}
+	if config.TLS.Cert != "" || config.TLS.Key != "" {
+		reloader, err := grpc.NewCertReloader(configFilePath(configPath, config.TLS.Cert),
+			configFilePath(configPath, config.TLS.Key),
+			configFilePath(configPath, config.TLS.ClientCA),
+			time.Duration(config.TLS.ReloadInterval)*time.Second, a.lg)
+		if err != nil {
+			return fmt.Errorf("failed to load tls certificate: %w", err)
+		}
+		serverOpts = append(serverOpts, gogrpc.Creds(reloader.Credentials()))
+
+		if config.TLS.ClientCA != "" {
+			a.lg.Printf("Requiring client certificates for: %s\n",
+				strings.Join(config.TLS.ClientCertMethods, ", "))
+			interceptors = append(interceptors,
+				grpc.ClientCertInterceptor(config.TLS.ClientCertMethods))
+		}
+	} else {
+		if config.TLS.ClientCA != "" {
+			return errors.New("a tls certificate is required to verify client certificates")
+		}
+		a.lg.Println("WARNING: tls certificate not set, gRPC server is using plaintext")
+	}
+
+	interceptors = append(interceptors, grpc.RateLimitInterceptor(limiter, limits, a.lg))
+	serverOpts = append(serverOpts, gogrpc.ChainUnaryInterceptor(interceptors...))
+
+	a.srv, err = grpc.NewServer(config.Address, serverOpts, services...)
 	if err != nil {
 		return fmt.Errorf("failed to create gRPC server: %w", err)
 	}
+	a.srv.WatchHealth(a.repo.Health, time.Duration(config.Repo.HealthInterval)*time.Second,
+		a.lg)
+
+	if config.HTTP.Address != "" {
+		a.lg.Printf("Creating HTTP server on: %s\n", config.HTTP.Address)
+
+		mux := gohttp.NewServeMux()
+		mux.Handle(http.JWKSPath, http.JWKSHandler(authSvc))
+
+		if config.HTTP.ForwardAuth.Enabled {
+			a.lg.Println("Serving forward auth endpoint")
+			mux.Handle(http.ForwardAuthPath, http.ForwardAuthHandler(authSvc,
+				config.HTTP.ForwardAuth.CookieName, a.lg))
+		}
+
+		if config.HTTP.Gateway.Enabled {
+			a.lg.Println("Serving JSON gateway")
+			// the interceptors of the gRPC server also apply to the gateway so it can't be
+			// used to get around rate limits or client certificate checks
+			http.RegisterGateway(mux, authGRPC, &http.GatewayOptions{
+				RefreshCookie: config.HTTP.Gateway.RefreshCookie,
+				CookieName:    config.HTTP.Gateway.CookieName,
+				CookieDomain:  config.HTTP.Gateway.CookieDomain,
+				CookiePath:    config.HTTP.Gateway.CookiePath,
+				Interceptors:  interceptors,
+			})
+		}
+
+		a.httpSrv, err = http.NewServer(config.HTTP.Address, mux)
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP server: %w", err)
+		}
+	}
 
 	return nil
 }
 
+// loadKeyring will create a keyring that signs with jwtKey and verifies with the previous keys
+// from the configuration and any previous HMAC secrets in JWT_PREVIOUS_SECRETS
+func loadKeyring(jwtKey *token.Key, configPath string,
+	previous []PreviousKeyConfig) (*token.Keyring, error) {
+	keys := []*token.Key{}
+	for _, prev := range previous {
+		key, err := token.LoadPublicKey(prev.Algorithm, configFilePath(configPath, prev.PublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load previous jwt key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	for _, secret := range strings.Split(os.Getenv("JWT_PREVIOUS_SECRETS"), ",") {
+		if secret != "" {
+			keys = append(keys, token.NewHMACKey(secret))
+		}
+	}
+
+	keyring, err := token.NewKeyring(jwtKey, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create jwt keyring: %w", err)
+	}
+
+	return keyring, nil
+}
+
+// logSecurityEvent will log security events emitted by the auth service
+func (a *App) logSecurityEvent(event *auth.SecurityEvent) {
+	a.secLg.Printf("%s: user: %s session: %s client: %s user agent: %s\n", event.Type,
+		event.UserId, event.SessionId, event.Device.ClientIP, event.Device.UserAgent)
+}
+
 // Start serving the gRPC server
 func (a *App) Start() error {
 	a.srv.Serve()
 	a.lg.Println("Started gRPC server")
-	sigChan := make(chan os.Signal)
+	if a.httpSrv != nil {
+		a.httpSrv.Serve()
+		a.lg.Println("Started HTTP server")
+	}
+	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt)
 	<-sigChan
 
+	if a.httpSrv != nil && a.httpSrv.Err() != nil {
+		return a.httpSrv.Err()
+	}
+
 	return a.srv.Err()
 }
 
@@ -93,7 +315,15 @@ func (a *App) Shutdown(ctx context.Context) error {
 	errs.Go(func() error {
 		return a.srv.Close(ctx)
 	})
+	if a.httpSrv != nil {
+		errs.Go(func() error {
+			return a.httpSrv.Close(ctx)
+		})
+	}
 	errs.Go(a.repo.Close)
+	if a.limitClient != nil {
+		errs.Go(a.limitClient.Close)
+	}
 
 	return errs.Wait()
 }
@@ -3,6 +3,8 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"math"
+	"path/filepath"
 
 	"github.com/spf13/viper"
 )
@@ -10,31 +12,141 @@ import (
 var ErrConfigNotExist = errors.New("config does not exist")
 
 type Configuration struct {
-	Address string
-	Repo    RepositoryConfig
-	Cipher  CipherConfig
-	Token   TokenConfig
+	Address  string
+	TLS      TLSConfig
+	HTTP     HTTPConfig
+	Repo     RepositoryConfig
+	Cipher   CipherConfig
+	Token    TokenConfig
+	Password PasswordConfig
+	Lockout  LockoutConfig
+	// limits of calls to the gRPC server
+	RateLimit RateLimitConfig
+	// roles that can be assigned to users keyed by name
+	Roles map[string]RoleConfig
+	// path to the YAML policy file used to authorize actions
+	Policy   string
+	MFA      MFAConfig
+	ExtAuthz ExtAuthzConfig
 }
 
 // SetDefaults will set the defaults for our config struct
 func (c *Configuration) SetDefaults() {
-	switch {
-	case c.Repo.FlushInterval == 0:
+	if c.Repo.FlushInterval == 0 {
 		c.Repo.FlushInterval = 15
-	case c.Cipher.SaltLength == 0:
+	}
+	if c.Repo.HealthInterval == 0 {
+		c.Repo.HealthInterval = 5
+	}
+	if c.Cipher.SaltLength == 0 {
 		c.Cipher.SaltLength = 16
-	case c.Token.Refresh.Expiration == 0:
+	}
+	if c.Token.Refresh.Expiration == 0 {
 		c.Token.Refresh.Expiration = 24
-	case c.Token.Refresh.Length == 0:
+	}
+	if c.Token.Refresh.Length == 0 {
 		c.Token.Refresh.Length = 32
-	case c.Token.Jwt.Expiration == 0:
+	}
+	if c.Token.Jwt.Expiration == 0 {
 		c.Token.Jwt.Expiration = 15
 	}
+	if c.Token.Jwt.Algorithm == "" {
+		c.Token.Jwt.Algorithm = "HS256"
+	}
+	if c.Password.MinLength == 0 {
+		c.Password.MinLength = 8
+	}
+	if c.Password.MaxLength == 0 {
+		c.Password.MaxLength = 128
+	}
+	if c.Lockout.Window == 0 {
+		c.Lockout.Window = 15
+	}
+	if c.Lockout.BackoffThreshold == 0 {
+		c.Lockout.BackoffThreshold = 5
+	}
+	if c.Lockout.BaseDelay == 0 {
+		c.Lockout.BaseDelay = 1
+	}
+	if c.Lockout.MaxDelay == 0 {
+		c.Lockout.MaxDelay = 300
+	}
+	if c.Lockout.Threshold == 0 {
+		c.Lockout.Threshold = 10
+	}
+	if c.Lockout.Duration == 0 {
+		c.Lockout.Duration = 15
+	}
+	if c.RateLimit.Burst == 0 {
+		c.RateLimit.Burst = int(math.Ceil(c.RateLimit.Rate))
+	}
+	for name, limit := range c.RateLimit.Methods {
+		if limit.Burst == 0 {
+			limit.Burst = int(math.Ceil(limit.Rate))
+			c.RateLimit.Methods[name] = limit
+		}
+	}
+	if c.TLS.ReloadInterval == 0 {
+		c.TLS.ReloadInterval = 60
+	}
+	if c.TLS.ClientCertMethods == nil {
+		c.TLS.ClientCertMethods = []string{"proto.auth.Admin", "ValidateJWT", "Authorize"}
+	}
+	if c.Policy == "" {
+		c.Policy = "policy.yml"
+	}
+	if c.MFA.Issuer == "" {
+		c.MFA.Issuer = "auth"
+	}
+	if c.MFA.ChallengeExpiration == 0 {
+		c.MFA.ChallengeExpiration = 5
+	}
+}
+
+type TLSConfig struct {
+	// paths to the PEM encoded certificate and key of the gRPC server, the server listens on
+	// plaintext TCP when they aren't set
+	Cert string
+	Key  string
+	// path to the PEM encoded CA bundle client certificates are verified against
+	ClientCA string
+	// services, method names or full methods that require a verified client certificate
+	// when a client CA is set
+	ClientCertMethods []string
+	// how often the certificate files are checked for changes (in seconds)
+	ReloadInterval int
+}
+
+type HTTPConfig struct {
+	// address the http server listens on, the server is disabled when empty
+	Address     string
+	Gateway     GatewayConfig
+	ForwardAuth ForwardAuthConfig
+}
+
+type ForwardAuthConfig struct {
+	// serve the forward auth endpoint used by reverse proxies
+	Enabled bool
+	// cookie the jwt is read from when the Authorization header isn't set
+	CookieName string
+}
+
+type GatewayConfig struct {
+	// serve JSON endpoints mirroring the Login, VerifyMFA, Refresh, ValidateJWT and Logout
+	// calls
+	Enabled bool
+	// set the refresh token in a HttpOnly, Secure and SameSite cookie instead of the body
+	RefreshCookie bool
+	CookieName    string
+	CookieDomain  string
+	CookiePath    string
 }
 
 type RepositoryConfig struct {
 	Address       string
 	FlushInterval int
+	// how often the connection to the repository is checked for health checks (in seconds)
+	HealthInterval int
 }
 
 type CipherConfig struct {
@@ -54,6 +166,93 @@ type RefreshConfig struct {
 
 type JWTConfig struct {
 	Expiration int
+	// iss claim of issued jwts
+	Issuer string
+	// aud claim of issued jwts, jwts must be intended for one of them to be valid
+	Audience []string
+	// leeway for clock skew when validating jwts (in seconds)
+	Leeway int
+	// signing algorithm, one of HS256, RS256, ES256 or EdDSA
+	Algorithm string
+	// path to the PEM encoded private key for asymmetric algorithms
+	PrivateKey string
+	// keys that used to sign jwts, tokens signed by them stay valid until they expire
+	PreviousKeys []PreviousKeyConfig
+}
+
+type PreviousKeyConfig struct {
+	Algorithm string
+	// path to the PEM encoded public key
+	PublicKey string
+}
+
+type RoleConfig struct {
+	Level       int
+	Permissions []string
+}
+
+type MFAConfig struct {
+	// issuer shown in authenticator apps
+	Issuer string
+	// how long a user has to provide their second factor after logging in (in minutes)
+	ChallengeExpiration int
+}
+
+type ExtAuthzConfig struct {
+	// serve the envoy.service.auth.v3.Authorization service on the gRPC server
+	Enabled bool
+	// cookie the jwt is read from when the Authorization header isn't set
+	CookieName string
+}
+
+type LockoutConfig struct {
+	// sliding window failed attempts are counted over (in minutes), negative to disable
+	Window int
+	// failed attempts of a user or client ip before back-off is applied
+	BackoffThreshold int
+	// delay once back-off is applied, doubled for every failed attempt (in seconds)
+	BaseDelay int
+	// longest delay back-off applies (in seconds)
+	MaxDelay int
+	// failed attempts of a user before their account is locked
+	Threshold int
+	// how long an account stays locked (in minutes)
+	Duration int
+}
+
+type RateLimitConfig struct {
+	// calls a peer can make to each method (per second), calls aren't limited when zero
+	Rate float64
+	// calls a peer can make at once, defaults to the rate
+	Burst int
+	// limits of methods keyed by method name, overriding the rate and burst
+	Methods map[string]LimitConfig
+	// share limits between replicas through the repository redis server
+	Redis bool
+}
+
+type LimitConfig struct {
+	Rate  float64
+	Burst int
+}
+
+type PasswordConfig struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+}
+
+// configFilePath will resolve a path from the configuration file relative to the
+// configuration directory
+func configFilePath(configDir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(configDir, path)
 }
 
 // ParseConfig will look for a config file in a specified directory.
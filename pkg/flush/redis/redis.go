@@ -19,7 +19,7 @@ func NewRedisFlusher(client *redis.Client) flush.Flusher {
 	return &redisFlush{client}
 }
 
-// Flush a redis database blacklist of all expired tokens
+// Flush a redis database blacklist of all expired jwt ids
 func (rf *redisFlush) Flush() error {
 	if err := rf.ZRemRangeByScore("blacklist", "0",
 		strconv.FormatInt(time.Now().Unix(), 10)).Err(); err != nil {
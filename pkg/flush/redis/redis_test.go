@@ -1,18 +1,24 @@
 package redis_test
 
 import (
+	"errors"
 	"os"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-redis/redis"
 	"github.com/joshturge-io/auth/pkg/flush"
 	redisFlush "github.com/joshturge-io/auth/pkg/flush/redis"
 )
 
-var flusher flush.Flusher
+var (
+	client  *redis.Client
+	flusher flush.Flusher
+)
 
 func init() {
-	client := redis.NewClient(&redis.Options{
+	client = redis.NewClient(&redis.Options{
 		Addr:     os.Getenv("REPO_ADDR"),
 		Password: os.Getenv("REPO_PSWD"),
 		DB:       0,
@@ -21,8 +27,28 @@ func init() {
 }
 
 func TestFlush(t *testing.T) {
+	expired := "expired_jti_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	active := "active_jti_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := client.ZAdd("blacklist", redis.Z{
+		Score:  float64(time.Now().Add(-time.Minute).Unix()),
+		Member: expired,
+	}, redis.Z{
+		Score:  float64(time.Now().Add(time.Minute).Unix()),
+		Member: active,
+	}).Err(); err != nil {
+		t.Fatal(err)
+	}
+
 	if err := flusher.Flush(); err != nil {
 		t.Error(err)
 		t.FailNow()
 	}
+
+	if err := client.ZRank("blacklist", expired).Err(); !errors.Is(err, redis.Nil) {
+		t.Errorf("expired jti was not flushed got: %v", err)
+	}
+
+	if err := client.ZRank("blacklist", active).Err(); err != nil {
+		t.Errorf("active jti should not be flushed got: %v", err)
+	}
 }
@@ -17,11 +17,17 @@ type Service struct {
 	lg       *log.Logger
 	wg       sync.WaitGroup
 	quit     chan struct{}
+	mu       sync.RWMutex
 	err      error
 }
 
 func NewService(lg *log.Logger, flush Flusher, flushInt time.Duration) *Service {
-	return &Service{flush, flushInt, lg, sync.WaitGroup{}, make(chan struct{}), nil}
+	return &Service{
+		flush:    flush,
+		flushInt: flushInt,
+		lg:       lg,
+		quit:     make(chan struct{}),
+	}
 }
 
 func (s *Service) Start() {
@@ -36,10 +42,13 @@ func (s *Service) Start() {
 			select {
 			case <-ticker.C:
 				s.lg.Println("Flushing blacklist...")
-				if err := s.flush.Flush(); err != nil {
+				err := s.flush.Flush()
+				if err != nil {
 					s.lg.Printf("ERROR: failed to flush: %s", err.Error())
-					s.err = err
 				}
+				s.mu.Lock()
+				s.err = err
+				s.mu.Unlock()
 			case <-s.quit:
 				ticker.Stop()
 				return
@@ -48,7 +57,11 @@ func (s *Service) Start() {
 	}()
 }
 
+// Err will return the error of the last flush, returns nil once a flush succeeds again
 func (s *Service) Err() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	return s.err
 }
 
@@ -24,6 +24,113 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// ErrorReason is a stable reason a call failed, sent in an ErrorInfo detail of
+// the status so clients don't need to parse status messages
+type ErrorReason int32
+
+const (
+	ErrorReason_ERROR_REASON_UNSPECIFIED ErrorReason = 0
+	ErrorReason_INTERNAL                 ErrorReason = 1
+	// the repository could not be reached
+	ErrorReason_REPOSITORY_UNAVAILABLE ErrorReason = 2
+	ErrorReason_TIMEOUT                ErrorReason = 3
+	ErrorReason_CANCELLED              ErrorReason = 4
+	ErrorReason_INVALID_CREDENTIALS    ErrorReason = 5
+	ErrorReason_USER_NOT_FOUND         ErrorReason = 6
+	ErrorReason_USER_EXISTS            ErrorReason = 7
+	ErrorReason_INVALID_USER_ID        ErrorReason = 8
+	ErrorReason_WEAK_PASSWORD          ErrorReason = 9
+	ErrorReason_INVALID_SESSION        ErrorReason = 10
+	ErrorReason_SESSION_NOT_FOUND      ErrorReason = 11
+	ErrorReason_REFRESH_TOKEN_REUSED   ErrorReason = 12
+	ErrorReason_INVALID_TOKEN          ErrorReason = 13
+	ErrorReason_TOKEN_EXPIRED          ErrorReason = 14
+	ErrorReason_ACCOUNT_LOCKED         ErrorReason = 15
+	ErrorReason_TOO_MANY_ATTEMPTS      ErrorReason = 16
+	ErrorReason_RATE_LIMITED           ErrorReason = 17
+	ErrorReason_MFA_REQUIRED           ErrorReason = 18
+	ErrorReason_INVALID_MFA_CODE       ErrorReason = 19
+	ErrorReason_INVALID_MFA_CHALLENGE  ErrorReason = 20
+	ErrorReason_MFA_ENABLED            ErrorReason = 21
+	ErrorReason_MFA_NOT_ENABLED        ErrorReason = 22
+	ErrorReason_MFA_NOT_ENROLLED       ErrorReason = 23
+	ErrorReason_ROLE_NOT_FOUND         ErrorReason = 24
+	ErrorReason_ADMIN_SECRET_REQUIRED  ErrorReason = 25
+	ErrorReason_INVALID_ADMIN_SECRET   ErrorReason = 26
+	// the method requires a verified client certificate
+	ErrorReason_CLIENT_CERT_REQUIRED ErrorReason = 27
+)
+
+var ErrorReason_name = map[int32]string{
+	0:  "ERROR_REASON_UNSPECIFIED",
+	1:  "INTERNAL",
+	2:  "REPOSITORY_UNAVAILABLE",
+	3:  "TIMEOUT",
+	4:  "CANCELLED",
+	5:  "INVALID_CREDENTIALS",
+	6:  "USER_NOT_FOUND",
+	7:  "USER_EXISTS",
+	8:  "INVALID_USER_ID",
+	9:  "WEAK_PASSWORD",
+	10: "INVALID_SESSION",
+	11: "SESSION_NOT_FOUND",
+	12: "REFRESH_TOKEN_REUSED",
+	13: "INVALID_TOKEN",
+	14: "TOKEN_EXPIRED",
+	15: "ACCOUNT_LOCKED",
+	16: "TOO_MANY_ATTEMPTS",
+	17: "RATE_LIMITED",
+	18: "MFA_REQUIRED",
+	19: "INVALID_MFA_CODE",
+	20: "INVALID_MFA_CHALLENGE",
+	21: "MFA_ENABLED",
+	22: "MFA_NOT_ENABLED",
+	23: "MFA_NOT_ENROLLED",
+	24: "ROLE_NOT_FOUND",
+	25: "ADMIN_SECRET_REQUIRED",
+	26: "INVALID_ADMIN_SECRET",
+	27: "CLIENT_CERT_REQUIRED",
+}
+
+var ErrorReason_value = map[string]int32{
+	"ERROR_REASON_UNSPECIFIED": 0,
+	"INTERNAL":                 1,
+	"REPOSITORY_UNAVAILABLE":   2,
+	"TIMEOUT":                  3,
+	"CANCELLED":                4,
+	"INVALID_CREDENTIALS":      5,
+	"USER_NOT_FOUND":           6,
+	"USER_EXISTS":              7,
+	"INVALID_USER_ID":          8,
+	"WEAK_PASSWORD":            9,
+	"INVALID_SESSION":          10,
+	"SESSION_NOT_FOUND":        11,
+	"REFRESH_TOKEN_REUSED":     12,
+	"INVALID_TOKEN":            13,
+	"TOKEN_EXPIRED":            14,
+	"ACCOUNT_LOCKED":           15,
+	"TOO_MANY_ATTEMPTS":        16,
+	"RATE_LIMITED":             17,
+	"MFA_REQUIRED":             18,
+	"INVALID_MFA_CODE":         19,
+	"INVALID_MFA_CHALLENGE":    20,
+	"MFA_ENABLED":              21,
+	"MFA_NOT_ENABLED":          22,
+	"MFA_NOT_ENROLLED":         23,
+	"ROLE_NOT_FOUND":           24,
+	"ADMIN_SECRET_REQUIRED":    25,
+	"INVALID_ADMIN_SECRET":     26,
+	"CLIENT_CERT_REQUIRED":     27,
+}
+
+func (x ErrorReason) String() string {
+	return proto.EnumName(ErrorReason_name, int32(x))
+}
+
+func (ErrorReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{0}
+}
+
 type Credentials struct {
 	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
 	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
@@ -71,13 +178,123 @@ func (m *Credentials) GetPassword() string {
 	return ""
 }
 
+type Registration struct {
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// create an initial session for the new user
+	CreateSession        bool     `protobuf:"varint,3,opt,name=create_session,json=createSession,proto3" json:"create_session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Registration) Reset()         { *m = Registration{} }
+func (m *Registration) String() string { return proto.CompactTextString(m) }
+func (*Registration) ProtoMessage()    {}
+func (*Registration) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{1}
+}
+
+func (m *Registration) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Registration.Unmarshal(m, b)
+}
+func (m *Registration) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Registration.Marshal(b, m, deterministic)
+}
+func (m *Registration) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Registration.Merge(m, src)
+}
+func (m *Registration) XXX_Size() int {
+	return xxx_messageInfo_Registration.Size(m)
+}
+func (m *Registration) XXX_DiscardUnknown() {
+	xxx_messageInfo_Registration.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Registration proto.InternalMessageInfo
+
+func (m *Registration) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *Registration) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *Registration) GetCreateSession() bool {
+	if m != nil {
+		return m.CreateSession
+	}
+	return false
+}
+
+type RegisterStatus struct {
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// only set when a session was requested
+	Session              *Session `protobuf:"bytes,2,opt,name=session,proto3" json:"session,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterStatus) Reset()         { *m = RegisterStatus{} }
+func (m *RegisterStatus) String() string { return proto.CompactTextString(m) }
+func (*RegisterStatus) ProtoMessage()    {}
+func (*RegisterStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{2}
+}
+
+func (m *RegisterStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterStatus.Unmarshal(m, b)
+}
+func (m *RegisterStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterStatus.Marshal(b, m, deterministic)
+}
+func (m *RegisterStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterStatus.Merge(m, src)
+}
+func (m *RegisterStatus) XXX_Size() int {
+	return xxx_messageInfo_RegisterStatus.Size(m)
+}
+func (m *RegisterStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterStatus proto.InternalMessageInfo
+
+func (m *RegisterStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *RegisterStatus) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
 type Session struct {
 	// expiration time can be worked out client side since our jwt holds
 	// the expiration time.
-	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Jwt                  string   `protobuf:"bytes,2,opt,name=jwt,proto3" json:"jwt,omitempty"`
-	RefreshToken         string   `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
-	RefreshExpiration    int64    `protobuf:"varint,4,opt,name=refresh_expiration,json=refreshExpiration,proto3" json:"refresh_expiration,omitempty"`
+	UserId            string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Jwt               string `protobuf:"bytes,2,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	RefreshToken      string `protobuf:"bytes,3,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	RefreshExpiration int64  `protobuf:"varint,4,opt,name=refresh_expiration,json=refreshExpiration,proto3" json:"refresh_expiration,omitempty"`
+	// identifies the session among the users other sessions
+	SessionId string `protobuf:"bytes,5,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	// set by Login instead of the tokens when the user has a second factor
+	// enabled, the mfa token is exchanged for a session with VerifyMFA
+	MfaRequired          bool     `protobuf:"varint,6,opt,name=mfa_required,json=mfaRequired,proto3" json:"mfa_required,omitempty"`
+	MfaToken             string   `protobuf:"bytes,7,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -87,7 +304,7 @@ func (m *Session) Reset()         { *m = Session{} }
 func (m *Session) String() string { return proto.CompactTextString(m) }
 func (*Session) ProtoMessage()    {}
 func (*Session) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8bbd6f3875b0e874, []int{1}
+	return fileDescriptor_8bbd6f3875b0e874, []int{3}
 }
 
 func (m *Session) XXX_Unmarshal(b []byte) error {
@@ -136,359 +353,2651 @@ func (m *Session) GetRefreshExpiration() int64 {
 	return 0
 }
 
-type LogoutStatus struct {
-	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+func (m *Session) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *Session) GetMfaRequired() bool {
+	if m != nil {
+		return m.MfaRequired
+	}
+	return false
+}
+
+func (m *Session) GetMfaToken() string {
+	if m != nil {
+		return m.MfaToken
+	}
+	return ""
+}
+
+type MFAVerification struct {
+	MfaToken string `protobuf:"bytes,1,opt,name=mfa_token,json=mfaToken,proto3" json:"mfa_token,omitempty"`
+	// a totp code or one of the users recovery codes
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *LogoutStatus) Reset()         { *m = LogoutStatus{} }
-func (m *LogoutStatus) String() string { return proto.CompactTextString(m) }
-func (*LogoutStatus) ProtoMessage()    {}
-func (*LogoutStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8bbd6f3875b0e874, []int{2}
+func (m *MFAVerification) Reset()         { *m = MFAVerification{} }
+func (m *MFAVerification) String() string { return proto.CompactTextString(m) }
+func (*MFAVerification) ProtoMessage()    {}
+func (*MFAVerification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{4}
 }
 
-func (m *LogoutStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogoutStatus.Unmarshal(m, b)
+func (m *MFAVerification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MFAVerification.Unmarshal(m, b)
 }
-func (m *LogoutStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogoutStatus.Marshal(b, m, deterministic)
+func (m *MFAVerification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MFAVerification.Marshal(b, m, deterministic)
 }
-func (m *LogoutStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogoutStatus.Merge(m, src)
+func (m *MFAVerification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MFAVerification.Merge(m, src)
 }
-func (m *LogoutStatus) XXX_Size() int {
-	return xxx_messageInfo_LogoutStatus.Size(m)
+func (m *MFAVerification) XXX_Size() int {
+	return xxx_messageInfo_MFAVerification.Size(m)
 }
-func (m *LogoutStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogoutStatus.DiscardUnknown(m)
+func (m *MFAVerification) XXX_DiscardUnknown() {
+	xxx_messageInfo_MFAVerification.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LogoutStatus proto.InternalMessageInfo
+var xxx_messageInfo_MFAVerification proto.InternalMessageInfo
 
-func (m *LogoutStatus) GetUserId() string {
+func (m *MFAVerification) GetMfaToken() string {
 	if m != nil {
-		return m.UserId
+		return m.MfaToken
 	}
 	return ""
 }
 
-func (m *LogoutStatus) GetSuccess() bool {
-	if m != nil {
-		return m.Success
-	}
-	return false
-}
-
-func (m *LogoutStatus) GetMsg() string {
+func (m *MFAVerification) GetCode() string {
 	if m != nil {
-		return m.Msg
+		return m.Code
 	}
 	return ""
 }
 
-type JWT struct {
-	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+type TOTPEnrollment struct {
+	Secret string `protobuf:"bytes,1,opt,name=secret,proto3" json:"secret,omitempty"`
+	// otpauth uri of the secret that can be shown as a QR code
+	Uri                  string   `protobuf:"bytes,2,opt,name=uri,proto3" json:"uri,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *JWT) Reset()         { *m = JWT{} }
-func (m *JWT) String() string { return proto.CompactTextString(m) }
-func (*JWT) ProtoMessage()    {}
-func (*JWT) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8bbd6f3875b0e874, []int{3}
+func (m *TOTPEnrollment) Reset()         { *m = TOTPEnrollment{} }
+func (m *TOTPEnrollment) String() string { return proto.CompactTextString(m) }
+func (*TOTPEnrollment) ProtoMessage()    {}
+func (*TOTPEnrollment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{5}
 }
 
-func (m *JWT) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JWT.Unmarshal(m, b)
+func (m *TOTPEnrollment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TOTPEnrollment.Unmarshal(m, b)
 }
-func (m *JWT) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JWT.Marshal(b, m, deterministic)
+func (m *TOTPEnrollment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TOTPEnrollment.Marshal(b, m, deterministic)
 }
-func (m *JWT) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JWT.Merge(m, src)
+func (m *TOTPEnrollment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOTPEnrollment.Merge(m, src)
 }
-func (m *JWT) XXX_Size() int {
-	return xxx_messageInfo_JWT.Size(m)
+func (m *TOTPEnrollment) XXX_Size() int {
+	return xxx_messageInfo_TOTPEnrollment.Size(m)
 }
-func (m *JWT) XXX_DiscardUnknown() {
-	xxx_messageInfo_JWT.DiscardUnknown(m)
+func (m *TOTPEnrollment) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOTPEnrollment.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JWT proto.InternalMessageInfo
+var xxx_messageInfo_TOTPEnrollment proto.InternalMessageInfo
 
-func (m *JWT) GetToken() string {
+func (m *TOTPEnrollment) GetSecret() string {
 	if m != nil {
-		return m.Token
+		return m.Secret
 	}
 	return ""
 }
 
-type ValidityStatus struct {
-	Valid                bool     `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+func (m *TOTPEnrollment) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+type TOTPCode struct {
+	Jwt string `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	// a totp code, disabling totp and regenerating recovery codes also accept
+	// one of the users recovery codes
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ValidityStatus) Reset()         { *m = ValidityStatus{} }
-func (m *ValidityStatus) String() string { return proto.CompactTextString(m) }
-func (*ValidityStatus) ProtoMessage()    {}
-func (*ValidityStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8bbd6f3875b0e874, []int{4}
+func (m *TOTPCode) Reset()         { *m = TOTPCode{} }
+func (m *TOTPCode) String() string { return proto.CompactTextString(m) }
+func (*TOTPCode) ProtoMessage()    {}
+func (*TOTPCode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{6}
 }
 
-func (m *ValidityStatus) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ValidityStatus.Unmarshal(m, b)
+func (m *TOTPCode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TOTPCode.Unmarshal(m, b)
 }
-func (m *ValidityStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ValidityStatus.Marshal(b, m, deterministic)
+func (m *TOTPCode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TOTPCode.Marshal(b, m, deterministic)
 }
-func (m *ValidityStatus) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ValidityStatus.Merge(m, src)
+func (m *TOTPCode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TOTPCode.Merge(m, src)
 }
-func (m *ValidityStatus) XXX_Size() int {
-	return xxx_messageInfo_ValidityStatus.Size(m)
+func (m *TOTPCode) XXX_Size() int {
+	return xxx_messageInfo_TOTPCode.Size(m)
 }
-func (m *ValidityStatus) XXX_DiscardUnknown() {
-	xxx_messageInfo_ValidityStatus.DiscardUnknown(m)
+func (m *TOTPCode) XXX_DiscardUnknown() {
+	xxx_messageInfo_TOTPCode.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ValidityStatus proto.InternalMessageInfo
+var xxx_messageInfo_TOTPCode proto.InternalMessageInfo
 
-func (m *ValidityStatus) GetValid() bool {
+func (m *TOTPCode) GetJwt() string {
 	if m != nil {
-		return m.Valid
+		return m.Jwt
 	}
-	return false
+	return ""
 }
 
-func init() {
-	proto.RegisterType((*Credentials)(nil), "proto.auth.Credentials")
-	proto.RegisterType((*Session)(nil), "proto.auth.Session")
-	proto.RegisterType((*LogoutStatus)(nil), "proto.auth.LogoutStatus")
-	proto.RegisterType((*JWT)(nil), "proto.auth.JWT")
-	proto.RegisterType((*ValidityStatus)(nil), "proto.auth.ValidityStatus")
+func (m *TOTPCode) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
 }
 
-func init() {
-	proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874)
+type MFAStatus struct {
+	UserId      string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success     bool   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Msg         string `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	TotpEnabled bool   `protobuf:"varint,4,opt,name=totp_enabled,json=totpEnabled,proto3" json:"totp_enabled,omitempty"`
+	// set when recovery codes are generated, they can't be retrieved again
+	RecoveryCodes []string `protobuf:"bytes,5,rep,name=recovery_codes,json=recoveryCodes,proto3" json:"recovery_codes,omitempty"`
+	// number of unused recovery codes
+	RecoveryCodesRemaining int32    `protobuf:"varint,6,opt,name=recovery_codes_remaining,json=recoveryCodesRemaining,proto3" json:"recovery_codes_remaining,omitempty"`
+	XXX_NoUnkeyedLiteral   struct{} `json:"-"`
+	XXX_unrecognized       []byte   `json:"-"`
+	XXX_sizecache          int32    `json:"-"`
 }
 
-var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 348 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x51, 0x51, 0x4f, 0xf2, 0x30,
-	0x14, 0xcd, 0xbe, 0x7d, 0x30, 0xbc, 0x20, 0x6a, 0x25, 0x61, 0x99, 0x2f, 0x64, 0x26, 0x86, 0x17,
-	0xf7, 0x20, 0x31, 0x26, 0xbe, 0x19, 0xc3, 0x83, 0x84, 0xa7, 0x41, 0xe4, 0x91, 0x54, 0x56, 0xa1,
-	0x0a, 0x2b, 0x69, 0xef, 0x44, 0xff, 0x81, 0x7f, 0xd7, 0x7f, 0x60, 0xda, 0x6e, 0xb8, 0x98, 0xf9,
-	0xd4, 0x9e, 0x73, 0xee, 0x6d, 0xcf, 0x3d, 0x17, 0x80, 0x66, 0xb8, 0x8a, 0xb6, 0x52, 0xa0, 0x20,
-	0x60, 0x8e, 0x48, 0x33, 0xe1, 0x10, 0x9a, 0xf7, 0x92, 0x25, 0x2c, 0x45, 0x4e, 0xd7, 0x8a, 0x04,
-	0xd0, 0xc8, 0x14, 0x93, 0x29, 0xdd, 0x30, 0xdf, 0xe9, 0x39, 0xfd, 0x83, 0x78, 0x8f, 0xb5, 0xb6,
-	0xa5, 0x4a, 0xed, 0x84, 0x4c, 0xfc, 0x7f, 0x56, 0x2b, 0x70, 0xf8, 0xe9, 0x80, 0x37, 0x61, 0x4a,
-	0x71, 0x91, 0x92, 0x2e, 0x78, 0xba, 0x67, 0xce, 0x93, 0xfc, 0x89, 0xba, 0x86, 0x0f, 0x09, 0x39,
-	0x06, 0xf7, 0x65, 0x87, 0x79, 0xaf, 0xbe, 0x92, 0x73, 0x38, 0x94, 0xec, 0x59, 0x32, 0xb5, 0x9a,
-	0xa3, 0x78, 0x65, 0xa9, 0xef, 0x1a, 0xad, 0x95, 0x93, 0x53, 0xcd, 0x91, 0x4b, 0x20, 0x45, 0x11,
-	0x7b, 0xdf, 0x72, 0x49, 0x91, 0x8b, 0xd4, 0xff, 0xdf, 0x73, 0xfa, 0x6e, 0x7c, 0x92, 0x2b, 0xc3,
-	0xbd, 0x10, 0x4e, 0xa0, 0x35, 0x16, 0x4b, 0x91, 0xe1, 0x04, 0x29, 0x66, 0xea, 0x6f, 0x3b, 0x3e,
-	0x78, 0x2a, 0x5b, 0x2c, 0x98, 0x52, 0xc6, 0x52, 0x23, 0x2e, 0xa0, 0x36, 0xba, 0x51, 0xcb, 0xdc,
-	0x8c, 0xbe, 0x86, 0x67, 0xe0, 0x8e, 0x66, 0x53, 0xd2, 0x81, 0x9a, 0xf5, 0x69, 0x5f, 0xb2, 0x20,
-	0xbc, 0x80, 0xf6, 0x23, 0x5d, 0xf3, 0x84, 0xe3, 0x47, 0xfe, 0x67, 0x07, 0x6a, 0x6f, 0x9a, 0x31,
-	0x75, 0x8d, 0xd8, 0x82, 0xab, 0x2f, 0x07, 0xda, 0x77, 0x19, 0xae, 0x74, 0xd8, 0x0b, 0x63, 0x96,
-	0x5c, 0x43, 0x6d, 0x2c, 0x96, 0x3c, 0x25, 0xdd, 0xe8, 0x67, 0x29, 0x51, 0x69, 0x23, 0xc1, 0x69,
-	0x59, 0x28, 0x22, 0x1e, 0x80, 0x17, 0xdb, 0xc1, 0x49, 0x95, 0x5e, 0xdd, 0x74, 0x0b, 0x4d, 0x63,
-	0x93, 0x22, 0xd3, 0xb3, 0x1c, 0x95, 0x6b, 0x46, 0xb3, 0x69, 0x10, 0x94, 0x89, 0x5f, 0x03, 0xdd,
-	0x40, 0xdd, 0x86, 0x5a, 0xfd, 0x9f, 0x5f, 0x26, 0xcb, 0xe9, 0x3f, 0xd5, 0x8d, 0x30, 0xf8, 0x0e,
-	0x00, 0x00, 0xff, 0xff, 0x47, 0xdf, 0x54, 0x91, 0x80, 0x02, 0x00, 0x00,
+func (m *MFAStatus) Reset()         { *m = MFAStatus{} }
+func (m *MFAStatus) String() string { return proto.CompactTextString(m) }
+func (*MFAStatus) ProtoMessage()    {}
+func (*MFAStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{7}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
-
-// AuthenticationClient is the client API for Authentication service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthenticationClient interface {
-	Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error)
-	Refresh(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error)
-	ValidateJWT(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*ValidityStatus, error)
-	Logout(ctx context.Context, in *Session, opts ...grpc.CallOption) (*LogoutStatus, error)
+func (m *MFAStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MFAStatus.Unmarshal(m, b)
+}
+func (m *MFAStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MFAStatus.Marshal(b, m, deterministic)
+}
+func (m *MFAStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MFAStatus.Merge(m, src)
+}
+func (m *MFAStatus) XXX_Size() int {
+	return xxx_messageInfo_MFAStatus.Size(m)
+}
+func (m *MFAStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_MFAStatus.DiscardUnknown(m)
 }
 
-type authenticationClient struct {
-	cc grpc.ClientConnInterface
+var xxx_messageInfo_MFAStatus proto.InternalMessageInfo
+
+func (m *MFAStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
 }
 
-func NewAuthenticationClient(cc grpc.ClientConnInterface) AuthenticationClient {
-	return &authenticationClient{cc}
+func (m *MFAStatus) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
 }
 
-func (c *authenticationClient) Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error) {
-	out := new(Session)
-	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Login", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MFAStatus) GetMsg() string {
+	if m != nil {
+		return m.Msg
 	}
-	return out, nil
+	return ""
 }
 
-func (c *authenticationClient) Refresh(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error) {
-	out := new(Session)
-	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Refresh", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MFAStatus) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
 	}
-	return out, nil
+	return false
 }
 
-func (c *authenticationClient) ValidateJWT(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*ValidityStatus, error) {
-	out := new(ValidityStatus)
-	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/ValidateJWT", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MFAStatus) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
 	}
-	return out, nil
+	return nil
 }
 
-func (c *authenticationClient) Logout(ctx context.Context, in *Session, opts ...grpc.CallOption) (*LogoutStatus, error) {
-	out := new(LogoutStatus)
-	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Logout", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *MFAStatus) GetRecoveryCodesRemaining() int32 {
+	if m != nil {
+		return m.RecoveryCodesRemaining
 	}
-	return out, nil
+	return 0
 }
 
-// AuthenticationServer is the server API for Authentication service.
-type AuthenticationServer interface {
-	Login(context.Context, *Credentials) (*Session, error)
-	Refresh(context.Context, *Session) (*Session, error)
-	ValidateJWT(context.Context, *JWT) (*ValidityStatus, error)
-	Logout(context.Context, *Session) (*LogoutStatus, error)
+// SessionInfo is a session without its tokens
+type SessionInfo struct {
+	SessionId            string   `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	UserId               string   `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	CreatedAt            int64    `protobuf:"varint,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	LastUsed             int64    `protobuf:"varint,4,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	RefreshExpiration    int64    `protobuf:"varint,5,opt,name=refresh_expiration,json=refreshExpiration,proto3" json:"refresh_expiration,omitempty"`
+	UserAgent            string   `protobuf:"bytes,6,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	ClientIp             string   `protobuf:"bytes,7,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-// UnimplementedAuthenticationServer can be embedded to have forward compatible implementations.
-type UnimplementedAuthenticationServer struct {
+func (m *SessionInfo) Reset()         { *m = SessionInfo{} }
+func (m *SessionInfo) String() string { return proto.CompactTextString(m) }
+func (*SessionInfo) ProtoMessage()    {}
+func (*SessionInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{8}
 }
 
-func (*UnimplementedAuthenticationServer) Login(ctx context.Context, req *Credentials) (*Session, error) {
+func (m *SessionInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionInfo.Unmarshal(m, b)
+}
+func (m *SessionInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionInfo.Marshal(b, m, deterministic)
+}
+func (m *SessionInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionInfo.Merge(m, src)
+}
+func (m *SessionInfo) XXX_Size() int {
+	return xxx_messageInfo_SessionInfo.Size(m)
+}
+func (m *SessionInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionInfo proto.InternalMessageInfo
+
+func (m *SessionInfo) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetLastUsed() int64 {
+	if m != nil {
+		return m.LastUsed
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetRefreshExpiration() int64 {
+	if m != nil {
+		return m.RefreshExpiration
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+type SessionList struct {
+	Sessions             []*SessionInfo `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *SessionList) Reset()         { *m = SessionList{} }
+func (m *SessionList) String() string { return proto.CompactTextString(m) }
+func (*SessionList) ProtoMessage()    {}
+func (*SessionList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{9}
+}
+
+func (m *SessionList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionList.Unmarshal(m, b)
+}
+func (m *SessionList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionList.Marshal(b, m, deterministic)
+}
+func (m *SessionList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionList.Merge(m, src)
+}
+func (m *SessionList) XXX_Size() int {
+	return xxx_messageInfo_SessionList.Size(m)
+}
+func (m *SessionList) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionList proto.InternalMessageInfo
+
+func (m *SessionList) GetSessions() []*SessionInfo {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+type SessionRevocation struct {
+	// jwt of the user revoking one of their sessions
+	Jwt                  string   `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	SessionId            string   `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SessionRevocation) Reset()         { *m = SessionRevocation{} }
+func (m *SessionRevocation) String() string { return proto.CompactTextString(m) }
+func (*SessionRevocation) ProtoMessage()    {}
+func (*SessionRevocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{10}
+}
+
+func (m *SessionRevocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionRevocation.Unmarshal(m, b)
+}
+func (m *SessionRevocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionRevocation.Marshal(b, m, deterministic)
+}
+func (m *SessionRevocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionRevocation.Merge(m, src)
+}
+func (m *SessionRevocation) XXX_Size() int {
+	return xxx_messageInfo_SessionRevocation.Size(m)
+}
+func (m *SessionRevocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionRevocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionRevocation proto.InternalMessageInfo
+
+func (m *SessionRevocation) GetJwt() string {
+	if m != nil {
+		return m.Jwt
+	}
+	return ""
+}
+
+func (m *SessionRevocation) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type UserRequest struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserRequest) Reset()         { *m = UserRequest{} }
+func (m *UserRequest) String() string { return proto.CompactTextString(m) }
+func (*UserRequest) ProtoMessage()    {}
+func (*UserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{11}
+}
+
+func (m *UserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserRequest.Unmarshal(m, b)
+}
+func (m *UserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserRequest.Marshal(b, m, deterministic)
+}
+func (m *UserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserRequest.Merge(m, src)
+}
+func (m *UserRequest) XXX_Size() int {
+	return xxx_messageInfo_UserRequest.Size(m)
+}
+func (m *UserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserRequest proto.InternalMessageInfo
+
+func (m *UserRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type UserSessionRevocation struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	SessionId            string   `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserSessionRevocation) Reset()         { *m = UserSessionRevocation{} }
+func (m *UserSessionRevocation) String() string { return proto.CompactTextString(m) }
+func (*UserSessionRevocation) ProtoMessage()    {}
+func (*UserSessionRevocation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{12}
+}
+
+func (m *UserSessionRevocation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserSessionRevocation.Unmarshal(m, b)
+}
+func (m *UserSessionRevocation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserSessionRevocation.Marshal(b, m, deterministic)
+}
+func (m *UserSessionRevocation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserSessionRevocation.Merge(m, src)
+}
+func (m *UserSessionRevocation) XXX_Size() int {
+	return xxx_messageInfo_UserSessionRevocation.Size(m)
+}
+func (m *UserSessionRevocation) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserSessionRevocation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserSessionRevocation proto.InternalMessageInfo
+
+func (m *UserSessionRevocation) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *UserSessionRevocation) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+type RevokeStatus struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeStatus) Reset()         { *m = RevokeStatus{} }
+func (m *RevokeStatus) String() string { return proto.CompactTextString(m) }
+func (*RevokeStatus) ProtoMessage()    {}
+func (*RevokeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{13}
+}
+
+func (m *RevokeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeStatus.Unmarshal(m, b)
+}
+func (m *RevokeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeStatus.Marshal(b, m, deterministic)
+}
+func (m *RevokeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeStatus.Merge(m, src)
+}
+func (m *RevokeStatus) XXX_Size() int {
+	return xxx_messageInfo_RevokeStatus.Size(m)
+}
+func (m *RevokeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeStatus proto.InternalMessageInfo
+
+func (m *RevokeStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *RevokeStatus) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *RevokeStatus) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type LogoutStatus struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogoutStatus) Reset()         { *m = LogoutStatus{} }
+func (m *LogoutStatus) String() string { return proto.CompactTextString(m) }
+func (*LogoutStatus) ProtoMessage()    {}
+func (*LogoutStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{14}
+}
+
+func (m *LogoutStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutStatus.Unmarshal(m, b)
+}
+func (m *LogoutStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutStatus.Marshal(b, m, deterministic)
+}
+func (m *LogoutStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutStatus.Merge(m, src)
+}
+func (m *LogoutStatus) XXX_Size() int {
+	return xxx_messageInfo_LogoutStatus.Size(m)
+}
+func (m *LogoutStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogoutStatus proto.InternalMessageInfo
+
+func (m *LogoutStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *LogoutStatus) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *LogoutStatus) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type PasswordChange struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	OldPassword          string   `protobuf:"bytes,2,opt,name=old_password,json=oldPassword,proto3" json:"old_password,omitempty"`
+	NewPassword          string   `protobuf:"bytes,3,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PasswordChange) Reset()         { *m = PasswordChange{} }
+func (m *PasswordChange) String() string { return proto.CompactTextString(m) }
+func (*PasswordChange) ProtoMessage()    {}
+func (*PasswordChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{15}
+}
+
+func (m *PasswordChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PasswordChange.Unmarshal(m, b)
+}
+func (m *PasswordChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PasswordChange.Marshal(b, m, deterministic)
+}
+func (m *PasswordChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PasswordChange.Merge(m, src)
+}
+func (m *PasswordChange) XXX_Size() int {
+	return xxx_messageInfo_PasswordChange.Size(m)
+}
+func (m *PasswordChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_PasswordChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PasswordChange proto.InternalMessageInfo
+
+func (m *PasswordChange) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PasswordChange) GetOldPassword() string {
+	if m != nil {
+		return m.OldPassword
+	}
+	return ""
+}
+
+func (m *PasswordChange) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
+	}
+	return ""
+}
+
+type PasswordReset struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	NewPassword          string   `protobuf:"bytes,2,opt,name=new_password,json=newPassword,proto3" json:"new_password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PasswordReset) Reset()         { *m = PasswordReset{} }
+func (m *PasswordReset) String() string { return proto.CompactTextString(m) }
+func (*PasswordReset) ProtoMessage()    {}
+func (*PasswordReset) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{16}
+}
+
+func (m *PasswordReset) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PasswordReset.Unmarshal(m, b)
+}
+func (m *PasswordReset) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PasswordReset.Marshal(b, m, deterministic)
+}
+func (m *PasswordReset) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PasswordReset.Merge(m, src)
+}
+func (m *PasswordReset) XXX_Size() int {
+	return xxx_messageInfo_PasswordReset.Size(m)
+}
+func (m *PasswordReset) XXX_DiscardUnknown() {
+	xxx_messageInfo_PasswordReset.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PasswordReset proto.InternalMessageInfo
+
+func (m *PasswordReset) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PasswordReset) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
+	}
+	return ""
+}
+
+type PasswordStatus struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PasswordStatus) Reset()         { *m = PasswordStatus{} }
+func (m *PasswordStatus) String() string { return proto.CompactTextString(m) }
+func (*PasswordStatus) ProtoMessage()    {}
+func (*PasswordStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{17}
+}
+
+func (m *PasswordStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PasswordStatus.Unmarshal(m, b)
+}
+func (m *PasswordStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PasswordStatus.Marshal(b, m, deterministic)
+}
+func (m *PasswordStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PasswordStatus.Merge(m, src)
+}
+func (m *PasswordStatus) XXX_Size() int {
+	return xxx_messageInfo_PasswordStatus.Size(m)
+}
+func (m *PasswordStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_PasswordStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PasswordStatus proto.InternalMessageInfo
+
+func (m *PasswordStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *PasswordStatus) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *PasswordStatus) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type JWT struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JWT) Reset()         { *m = JWT{} }
+func (m *JWT) String() string { return proto.CompactTextString(m) }
+func (*JWT) ProtoMessage()    {}
+func (*JWT) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{18}
+}
+
+func (m *JWT) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JWT.Unmarshal(m, b)
+}
+func (m *JWT) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JWT.Marshal(b, m, deterministic)
+}
+func (m *JWT) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JWT.Merge(m, src)
+}
+func (m *JWT) XXX_Size() int {
+	return xxx_messageInfo_JWT.Size(m)
+}
+func (m *JWT) XXX_DiscardUnknown() {
+	xxx_messageInfo_JWT.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JWT proto.InternalMessageInfo
+
+func (m *JWT) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ValidityStatus struct {
+	Valid                bool     `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidityStatus) Reset()         { *m = ValidityStatus{} }
+func (m *ValidityStatus) String() string { return proto.CompactTextString(m) }
+func (*ValidityStatus) ProtoMessage()    {}
+func (*ValidityStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{19}
+}
+
+func (m *ValidityStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidityStatus.Unmarshal(m, b)
+}
+func (m *ValidityStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidityStatus.Marshal(b, m, deterministic)
+}
+func (m *ValidityStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidityStatus.Merge(m, src)
+}
+func (m *ValidityStatus) XXX_Size() int {
+	return xxx_messageInfo_ValidityStatus.Size(m)
+}
+func (m *ValidityStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidityStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidityStatus proto.InternalMessageInfo
+
+func (m *ValidityStatus) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+type RoleAssignment struct {
+	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Role                 string   `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoleAssignment) Reset()         { *m = RoleAssignment{} }
+func (m *RoleAssignment) String() string { return proto.CompactTextString(m) }
+func (*RoleAssignment) ProtoMessage()    {}
+func (*RoleAssignment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{20}
+}
+
+func (m *RoleAssignment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleAssignment.Unmarshal(m, b)
+}
+func (m *RoleAssignment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleAssignment.Marshal(b, m, deterministic)
+}
+func (m *RoleAssignment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleAssignment.Merge(m, src)
+}
+func (m *RoleAssignment) XXX_Size() int {
+	return xxx_messageInfo_RoleAssignment.Size(m)
+}
+func (m *RoleAssignment) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleAssignment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleAssignment proto.InternalMessageInfo
+
+func (m *RoleAssignment) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RoleAssignment) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type RoleStatus struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Success              bool     `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Msg                  string   `protobuf:"bytes,3,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoleStatus) Reset()         { *m = RoleStatus{} }
+func (m *RoleStatus) String() string { return proto.CompactTextString(m) }
+func (*RoleStatus) ProtoMessage()    {}
+func (*RoleStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{21}
+}
+
+func (m *RoleStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoleStatus.Unmarshal(m, b)
+}
+func (m *RoleStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoleStatus.Marshal(b, m, deterministic)
+}
+func (m *RoleStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoleStatus.Merge(m, src)
+}
+func (m *RoleStatus) XXX_Size() int {
+	return xxx_messageInfo_RoleStatus.Size(m)
+}
+func (m *RoleStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoleStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoleStatus proto.InternalMessageInfo
+
+func (m *RoleStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *RoleStatus) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *RoleStatus) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+type UserRoles struct {
+	UserId               string   `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Roles                []string `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	Permissions          []string `protobuf:"bytes,3,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	PermLevel            int32    `protobuf:"varint,4,opt,name=perm_level,json=permLevel,proto3" json:"perm_level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserRoles) Reset()         { *m = UserRoles{} }
+func (m *UserRoles) String() string { return proto.CompactTextString(m) }
+func (*UserRoles) ProtoMessage()    {}
+func (*UserRoles) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{22}
+}
+
+func (m *UserRoles) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserRoles.Unmarshal(m, b)
+}
+func (m *UserRoles) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserRoles.Marshal(b, m, deterministic)
+}
+func (m *UserRoles) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserRoles.Merge(m, src)
+}
+func (m *UserRoles) XXX_Size() int {
+	return xxx_messageInfo_UserRoles.Size(m)
+}
+func (m *UserRoles) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserRoles.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserRoles proto.InternalMessageInfo
+
+func (m *UserRoles) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *UserRoles) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *UserRoles) GetPermissions() []string {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+func (m *UserRoles) GetPermLevel() int32 {
+	if m != nil {
+		return m.PermLevel
+	}
+	return 0
+}
+
+type LockoutRequest struct {
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	// failed attempts of the client ip are included when set
+	ClientIp             string   `protobuf:"bytes,2,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LockoutRequest) Reset()         { *m = LockoutRequest{} }
+func (m *LockoutRequest) String() string { return proto.CompactTextString(m) }
+func (*LockoutRequest) ProtoMessage()    {}
+func (*LockoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{23}
+}
+
+func (m *LockoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockoutRequest.Unmarshal(m, b)
+}
+func (m *LockoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockoutRequest.Marshal(b, m, deterministic)
+}
+func (m *LockoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockoutRequest.Merge(m, src)
+}
+func (m *LockoutRequest) XXX_Size() int {
+	return xxx_messageInfo_LockoutRequest.Size(m)
+}
+func (m *LockoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockoutRequest proto.InternalMessageInfo
+
+func (m *LockoutRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *LockoutRequest) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+type LockoutStatus struct {
+	UserId   string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ClientIp string `protobuf:"bytes,2,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	Locked   bool   `protobuf:"varint,3,opt,name=locked,proto3" json:"locked,omitempty"`
+	// unix time the account is locked until
+	LockedUntil int64 `protobuf:"varint,4,opt,name=locked_until,json=lockedUntil,proto3" json:"locked_until,omitempty"`
+	// failed attempts within the lockout window
+	UserFailures int32 `protobuf:"varint,5,opt,name=user_failures,json=userFailures,proto3" json:"user_failures,omitempty"`
+	IpFailures   int32 `protobuf:"varint,6,opt,name=ip_failures,json=ipFailures,proto3" json:"ip_failures,omitempty"`
+	// unix time back-off allows the next attempt at, zero when allowed now
+	RetryAt              int64    `protobuf:"varint,7,opt,name=retry_at,json=retryAt,proto3" json:"retry_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LockoutStatus) Reset()         { *m = LockoutStatus{} }
+func (m *LockoutStatus) String() string { return proto.CompactTextString(m) }
+func (*LockoutStatus) ProtoMessage()    {}
+func (*LockoutStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{24}
+}
+
+func (m *LockoutStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LockoutStatus.Unmarshal(m, b)
+}
+func (m *LockoutStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LockoutStatus.Marshal(b, m, deterministic)
+}
+func (m *LockoutStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LockoutStatus.Merge(m, src)
+}
+func (m *LockoutStatus) XXX_Size() int {
+	return xxx_messageInfo_LockoutStatus.Size(m)
+}
+func (m *LockoutStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_LockoutStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LockoutStatus proto.InternalMessageInfo
+
+func (m *LockoutStatus) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *LockoutStatus) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+func (m *LockoutStatus) GetLocked() bool {
+	if m != nil {
+		return m.Locked
+	}
+	return false
+}
+
+func (m *LockoutStatus) GetLockedUntil() int64 {
+	if m != nil {
+		return m.LockedUntil
+	}
+	return 0
+}
+
+func (m *LockoutStatus) GetUserFailures() int32 {
+	if m != nil {
+		return m.UserFailures
+	}
+	return 0
+}
+
+func (m *LockoutStatus) GetIpFailures() int32 {
+	if m != nil {
+		return m.IpFailures
+	}
+	return 0
+}
+
+func (m *LockoutStatus) GetRetryAt() int64 {
+	if m != nil {
+		return m.RetryAt
+	}
+	return 0
+}
+
+type AuthorizeRequest struct {
+	Jwt                  string   `protobuf:"bytes,1,opt,name=jwt,proto3" json:"jwt,omitempty"`
+	Action               string   `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	Resource             string   `protobuf:"bytes,3,opt,name=resource,proto3" json:"resource,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthorizeRequest) Reset()         { *m = AuthorizeRequest{} }
+func (m *AuthorizeRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthorizeRequest) ProtoMessage()    {}
+func (*AuthorizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{25}
+}
+
+func (m *AuthorizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizeRequest.Unmarshal(m, b)
+}
+func (m *AuthorizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizeRequest.Marshal(b, m, deterministic)
+}
+func (m *AuthorizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeRequest.Merge(m, src)
+}
+func (m *AuthorizeRequest) XXX_Size() int {
+	return xxx_messageInfo_AuthorizeRequest.Size(m)
+}
+func (m *AuthorizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizeRequest proto.InternalMessageInfo
+
+func (m *AuthorizeRequest) GetJwt() string {
+	if m != nil {
+		return m.Jwt
+	}
+	return ""
+}
+
+func (m *AuthorizeRequest) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuthorizeRequest) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+type AuthorizeResponse struct {
+	Allowed              bool     `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuthorizeResponse) Reset()         { *m = AuthorizeResponse{} }
+func (m *AuthorizeResponse) String() string { return proto.CompactTextString(m) }
+func (*AuthorizeResponse) ProtoMessage()    {}
+func (*AuthorizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{26}
+}
+
+func (m *AuthorizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuthorizeResponse.Unmarshal(m, b)
+}
+func (m *AuthorizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuthorizeResponse.Marshal(b, m, deterministic)
+}
+func (m *AuthorizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthorizeResponse.Merge(m, src)
+}
+func (m *AuthorizeResponse) XXX_Size() int {
+	return xxx_messageInfo_AuthorizeResponse.Size(m)
+}
+func (m *AuthorizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthorizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthorizeResponse proto.InternalMessageInfo
+
+func (m *AuthorizeResponse) GetAllowed() bool {
+	if m != nil {
+		return m.Allowed
+	}
+	return false
+}
+
+func (m *AuthorizeResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type KeySetRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeySetRequest) Reset()         { *m = KeySetRequest{} }
+func (m *KeySetRequest) String() string { return proto.CompactTextString(m) }
+func (*KeySetRequest) ProtoMessage()    {}
+func (*KeySetRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{27}
+}
+
+func (m *KeySetRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySetRequest.Unmarshal(m, b)
+}
+func (m *KeySetRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySetRequest.Marshal(b, m, deterministic)
+}
+func (m *KeySetRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySetRequest.Merge(m, src)
+}
+func (m *KeySetRequest) XXX_Size() int {
+	return xxx_messageInfo_KeySetRequest.Size(m)
+}
+func (m *KeySetRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySetRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySetRequest proto.InternalMessageInfo
+
+// JWK is the public half of a jwt signing key as described in RFC 7517
+type JWK struct {
+	Kty                  string   `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	Kid                  string   `protobuf:"bytes,2,opt,name=kid,proto3" json:"kid,omitempty"`
+	Use                  string   `protobuf:"bytes,3,opt,name=use,proto3" json:"use,omitempty"`
+	Alg                  string   `protobuf:"bytes,4,opt,name=alg,proto3" json:"alg,omitempty"`
+	N                    string   `protobuf:"bytes,5,opt,name=n,proto3" json:"n,omitempty"`
+	E                    string   `protobuf:"bytes,6,opt,name=e,proto3" json:"e,omitempty"`
+	Crv                  string   `protobuf:"bytes,7,opt,name=crv,proto3" json:"crv,omitempty"`
+	X                    string   `protobuf:"bytes,8,opt,name=x,proto3" json:"x,omitempty"`
+	Y                    string   `protobuf:"bytes,9,opt,name=y,proto3" json:"y,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JWK) Reset()         { *m = JWK{} }
+func (m *JWK) String() string { return proto.CompactTextString(m) }
+func (*JWK) ProtoMessage()    {}
+func (*JWK) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{28}
+}
+
+func (m *JWK) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JWK.Unmarshal(m, b)
+}
+func (m *JWK) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JWK.Marshal(b, m, deterministic)
+}
+func (m *JWK) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JWK.Merge(m, src)
+}
+func (m *JWK) XXX_Size() int {
+	return xxx_messageInfo_JWK.Size(m)
+}
+func (m *JWK) XXX_DiscardUnknown() {
+	xxx_messageInfo_JWK.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JWK proto.InternalMessageInfo
+
+func (m *JWK) GetKty() string {
+	if m != nil {
+		return m.Kty
+	}
+	return ""
+}
+
+func (m *JWK) GetKid() string {
+	if m != nil {
+		return m.Kid
+	}
+	return ""
+}
+
+func (m *JWK) GetUse() string {
+	if m != nil {
+		return m.Use
+	}
+	return ""
+}
+
+func (m *JWK) GetAlg() string {
+	if m != nil {
+		return m.Alg
+	}
+	return ""
+}
+
+func (m *JWK) GetN() string {
+	if m != nil {
+		return m.N
+	}
+	return ""
+}
+
+func (m *JWK) GetE() string {
+	if m != nil {
+		return m.E
+	}
+	return ""
+}
+
+func (m *JWK) GetCrv() string {
+	if m != nil {
+		return m.Crv
+	}
+	return ""
+}
+
+func (m *JWK) GetX() string {
+	if m != nil {
+		return m.X
+	}
+	return ""
+}
+
+func (m *JWK) GetY() string {
+	if m != nil {
+		return m.Y
+	}
+	return ""
+}
+
+type KeySet struct {
+	Keys                 []*JWK   `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeySet) Reset()         { *m = KeySet{} }
+func (m *KeySet) String() string { return proto.CompactTextString(m) }
+func (*KeySet) ProtoMessage()    {}
+func (*KeySet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{29}
+}
+
+func (m *KeySet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySet.Unmarshal(m, b)
+}
+func (m *KeySet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySet.Marshal(b, m, deterministic)
+}
+func (m *KeySet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySet.Merge(m, src)
+}
+func (m *KeySet) XXX_Size() int {
+	return xxx_messageInfo_KeySet.Size(m)
+}
+func (m *KeySet) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySet proto.InternalMessageInfo
+
+func (m *KeySet) GetKeys() []*JWK {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type ErrorInfo struct {
+	Reason               ErrorReason `protobuf:"varint,1,opt,name=reason,proto3,enum=proto.auth.ErrorReason" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ErrorInfo) Reset()         { *m = ErrorInfo{} }
+func (m *ErrorInfo) String() string { return proto.CompactTextString(m) }
+func (*ErrorInfo) ProtoMessage()    {}
+func (*ErrorInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8bbd6f3875b0e874, []int{30}
+}
+
+func (m *ErrorInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ErrorInfo.Unmarshal(m, b)
+}
+func (m *ErrorInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ErrorInfo.Marshal(b, m, deterministic)
+}
+func (m *ErrorInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ErrorInfo.Merge(m, src)
+}
+func (m *ErrorInfo) XXX_Size() int {
+	return xxx_messageInfo_ErrorInfo.Size(m)
+}
+func (m *ErrorInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_ErrorInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ErrorInfo proto.InternalMessageInfo
+
+func (m *ErrorInfo) GetReason() ErrorReason {
+	if m != nil {
+		return m.Reason
+	}
+	return ErrorReason_ERROR_REASON_UNSPECIFIED
+}
+
+func init() {
+	proto.RegisterEnum("proto.auth.ErrorReason", ErrorReason_name, ErrorReason_value)
+	proto.RegisterType((*Credentials)(nil), "proto.auth.Credentials")
+	proto.RegisterType((*Registration)(nil), "proto.auth.Registration")
+	proto.RegisterType((*RegisterStatus)(nil), "proto.auth.RegisterStatus")
+	proto.RegisterType((*Session)(nil), "proto.auth.Session")
+	proto.RegisterType((*MFAVerification)(nil), "proto.auth.MFAVerification")
+	proto.RegisterType((*TOTPEnrollment)(nil), "proto.auth.TOTPEnrollment")
+	proto.RegisterType((*TOTPCode)(nil), "proto.auth.TOTPCode")
+	proto.RegisterType((*MFAStatus)(nil), "proto.auth.MFAStatus")
+	proto.RegisterType((*SessionInfo)(nil), "proto.auth.SessionInfo")
+	proto.RegisterType((*SessionList)(nil), "proto.auth.SessionList")
+	proto.RegisterType((*SessionRevocation)(nil), "proto.auth.SessionRevocation")
+	proto.RegisterType((*UserRequest)(nil), "proto.auth.UserRequest")
+	proto.RegisterType((*UserSessionRevocation)(nil), "proto.auth.UserSessionRevocation")
+	proto.RegisterType((*RevokeStatus)(nil), "proto.auth.RevokeStatus")
+	proto.RegisterType((*LogoutStatus)(nil), "proto.auth.LogoutStatus")
+	proto.RegisterType((*PasswordChange)(nil), "proto.auth.PasswordChange")
+	proto.RegisterType((*PasswordReset)(nil), "proto.auth.PasswordReset")
+	proto.RegisterType((*PasswordStatus)(nil), "proto.auth.PasswordStatus")
+	proto.RegisterType((*JWT)(nil), "proto.auth.JWT")
+	proto.RegisterType((*ValidityStatus)(nil), "proto.auth.ValidityStatus")
+	proto.RegisterType((*RoleAssignment)(nil), "proto.auth.RoleAssignment")
+	proto.RegisterType((*RoleStatus)(nil), "proto.auth.RoleStatus")
+	proto.RegisterType((*UserRoles)(nil), "proto.auth.UserRoles")
+	proto.RegisterType((*LockoutRequest)(nil), "proto.auth.LockoutRequest")
+	proto.RegisterType((*LockoutStatus)(nil), "proto.auth.LockoutStatus")
+	proto.RegisterType((*AuthorizeRequest)(nil), "proto.auth.AuthorizeRequest")
+	proto.RegisterType((*AuthorizeResponse)(nil), "proto.auth.AuthorizeResponse")
+	proto.RegisterType((*KeySetRequest)(nil), "proto.auth.KeySetRequest")
+	proto.RegisterType((*JWK)(nil), "proto.auth.JWK")
+	proto.RegisterType((*KeySet)(nil), "proto.auth.KeySet")
+	proto.RegisterType((*ErrorInfo)(nil), "proto.auth.ErrorInfo")
+}
+
+func init() {
+	proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874)
+}
+
+var fileDescriptor_8bbd6f3875b0e874 = []byte{
+	// 1925 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0xdb, 0x72, 0xdb, 0xc8,
+	0xd1, 0xfe, 0x29, 0x8a, 0x22, 0xd9, 0x3c, 0x08, 0x1a, 0x4b, 0x32, 0x45, 0xaf, 0xeb, 0x97, 0xb1,
+	0x95, 0x94, 0x93, 0x2a, 0x3b, 0x29, 0xbb, 0x92, 0x38, 0xae, 0x1c, 0x0c, 0x93, 0x43, 0x09, 0x2b,
+	0x8a, 0xd4, 0x0e, 0x40, 0xdb, 0x7b, 0x85, 0xc2, 0x92, 0x23, 0x1a, 0x11, 0x08, 0x30, 0x00, 0x28,
+	0x99, 0xc9, 0x55, 0x9e, 0x22, 0x8f, 0x92, 0x17, 0xc9, 0x0b, 0x24, 0x37, 0xb9, 0xc9, 0x43, 0xa4,
+	0x7a, 0x30, 0x20, 0x01, 0x92, 0x92, 0xe2, 0x8d, 0xaf, 0x80, 0xfe, 0xba, 0xa7, 0x4f, 0xd3, 0xd3,
+	0xd3, 0x03, 0x60, 0xcf, 0xa2, 0x8f, 0xcf, 0xa7, 0x81, 0x1f, 0xf9, 0x04, 0xc4, 0xe7, 0x39, 0x22,
+	0x2a, 0x85, 0x4a, 0x2b, 0xe0, 0x23, 0xee, 0x45, 0x8e, 0xed, 0x86, 0xa4, 0x09, 0xa5, 0x59, 0xc8,
+	0x03, 0xcf, 0x9e, 0xf0, 0x46, 0xee, 0x38, 0xf7, 0xb4, 0xcc, 0x16, 0x34, 0xf2, 0xa6, 0x76, 0x18,
+	0xde, 0xf8, 0xc1, 0xa8, 0xb1, 0x15, 0xf3, 0x12, 0x5a, 0x9d, 0x40, 0x95, 0xf1, 0xb1, 0x13, 0x46,
+	0x81, 0x1d, 0x39, 0xbe, 0xf7, 0x43, 0xf5, 0x90, 0x1f, 0x41, 0x7d, 0x18, 0x70, 0x3b, 0xe2, 0x56,
+	0xc8, 0xc3, 0xd0, 0xf1, 0xbd, 0x46, 0xfe, 0x38, 0xf7, 0xb4, 0xc4, 0x6a, 0x31, 0x6a, 0xc4, 0xa0,
+	0xfa, 0x01, 0xea, 0xb1, 0x39, 0x1e, 0x18, 0x91, 0x1d, 0xcd, 0x42, 0xf2, 0x10, 0x8a, 0x68, 0xc0,
+	0x72, 0x46, 0xd2, 0xde, 0x0e, 0x92, 0xfa, 0x88, 0x3c, 0x83, 0x62, 0xa2, 0x0a, 0x8d, 0x55, 0x5e,
+	0x3c, 0x78, 0xbe, 0x0c, 0xff, 0xb9, 0x54, 0xc8, 0x12, 0x19, 0xf5, 0x5f, 0x39, 0x28, 0x4a, 0xf0,
+	0x76, 0x9d, 0x0a, 0xe4, 0xff, 0x70, 0x13, 0x49, 0xe7, 0xf1, 0x97, 0x7c, 0x0d, 0xb5, 0x80, 0x5f,
+	0x06, 0x3c, 0xfc, 0x68, 0x45, 0xfe, 0x15, 0x8f, 0xdd, 0x2e, 0xb3, 0xaa, 0x04, 0x4d, 0xc4, 0xc8,
+	0x33, 0x20, 0x89, 0x10, 0xff, 0x34, 0x75, 0xe2, 0x54, 0x35, 0xb6, 0x8f, 0x73, 0x4f, 0xf3, 0x6c,
+	0x4f, 0x72, 0xe8, 0x82, 0x41, 0x1e, 0x03, 0x48, 0xaf, 0xd0, 0x83, 0x82, 0x50, 0x58, 0x96, 0x88,
+	0x3e, 0x22, 0x4f, 0xa0, 0x3a, 0xb9, 0xb4, 0xad, 0x80, 0xff, 0x71, 0xe6, 0x04, 0x7c, 0xd4, 0xd8,
+	0x11, 0x89, 0xaa, 0x4c, 0x2e, 0x6d, 0x26, 0x21, 0xf2, 0x08, 0xca, 0x28, 0x12, 0x7b, 0x54, 0x8c,
+	0x53, 0x3d, 0xb9, 0xb4, 0x85, 0x37, 0xea, 0x5b, 0xd8, 0x3d, 0xef, 0x68, 0xef, 0x78, 0xe0, 0x5c,
+	0x3a, 0xc3, 0xd8, 0x62, 0x46, 0x3e, 0x97, 0x95, 0x27, 0x04, 0xb6, 0x87, 0xfe, 0x88, 0xcb, 0xa8,
+	0xc5, 0xbf, 0xfa, 0x1a, 0xea, 0x66, 0xdf, 0xbc, 0xa0, 0x5e, 0xe0, 0xbb, 0xee, 0x84, 0x7b, 0x11,
+	0x39, 0x84, 0x9d, 0x90, 0x0f, 0x03, 0x1e, 0x25, 0x29, 0x8b, 0x29, 0x4c, 0xd9, 0x2c, 0x70, 0x92,
+	0x94, 0xcd, 0x02, 0x47, 0xfd, 0x39, 0x94, 0x70, 0x6d, 0xcb, 0x1f, 0xf1, 0x24, 0xa1, 0xb9, 0x65,
+	0x42, 0x37, 0x59, 0xfb, 0x7b, 0x0e, 0xca, 0xe7, 0x1d, 0xed, 0xbe, 0x1d, 0x6f, 0x40, 0x31, 0x9c,
+	0x0d, 0x87, 0x3c, 0x0c, 0xc5, 0xea, 0x12, 0x4b, 0x48, 0x34, 0x33, 0x09, 0xc7, 0x72, 0x6f, 0xf0,
+	0x17, 0x93, 0x18, 0xf9, 0xd1, 0xd4, 0xe2, 0x9e, 0xfd, 0xbd, 0xcb, 0x47, 0x62, 0x33, 0x4a, 0xac,
+	0x82, 0x18, 0x8d, 0x21, 0x2c, 0xc9, 0x80, 0x0f, 0xfd, 0x6b, 0x1e, 0xcc, 0x2d, 0x74, 0x23, 0x6c,
+	0x14, 0x8e, 0xf3, 0x4f, 0xcb, 0xac, 0x96, 0xa0, 0x18, 0x41, 0x48, 0x5e, 0x41, 0x23, 0x2b, 0x66,
+	0x05, 0x7c, 0x62, 0x3b, 0x9e, 0xe3, 0x8d, 0xc5, 0xd6, 0x14, 0xd8, 0x61, 0x66, 0x01, 0x4b, 0xb8,
+	0xea, 0xbf, 0x73, 0x50, 0x91, 0x25, 0xa7, 0x7b, 0x97, 0xfe, 0xca, 0xbe, 0xe7, 0x56, 0xf7, 0x3d,
+	0x15, 0xf7, 0x56, 0x26, 0xee, 0xc7, 0x00, 0xf1, 0x29, 0x19, 0x59, 0x76, 0x24, 0x82, 0xcc, 0xb3,
+	0xb2, 0x44, 0xb4, 0x08, 0x37, 0xd7, 0xb5, 0xc3, 0xc8, 0x9a, 0x85, 0x32, 0xce, 0x3c, 0x2b, 0x21,
+	0x30, 0x08, 0xf9, 0xe8, 0x96, 0xd2, 0x2c, 0xdc, 0x51, 0x9a, 0xc2, 0x07, 0x7b, 0xcc, 0xbd, 0x48,
+	0x84, 0x57, 0x66, 0x65, 0x44, 0x34, 0x04, 0xd0, 0xd4, 0xd0, 0x75, 0xb8, 0x17, 0x59, 0xce, 0x34,
+	0xa9, 0xbb, 0x18, 0xd0, 0xa7, 0xea, 0xdb, 0x45, 0xb4, 0x5d, 0x27, 0x8c, 0xc8, 0x4b, 0x28, 0xc9,
+	0xd8, 0xc2, 0x46, 0xee, 0x38, 0xff, 0xb4, 0xf2, 0xe2, 0xe1, 0x86, 0x03, 0x8a, 0x89, 0x61, 0x0b,
+	0x41, 0xb5, 0x0d, 0x7b, 0x92, 0xc1, 0xf8, 0xb5, 0x2f, 0xab, 0x77, 0xbd, 0x88, 0xb2, 0x99, 0xdc,
+	0x5a, 0xc9, 0xa4, 0xfa, 0x13, 0xa8, 0x0c, 0x42, 0x1e, 0xe0, 0x71, 0xe1, 0x61, 0x74, 0x57, 0xcf,
+	0x52, 0x19, 0x1c, 0xa0, 0xe8, 0xba, 0xd1, 0x3b, 0x16, 0xdd, 0x67, 0xde, 0xc0, 0x9e, 0x79, 0xed,
+	0x5f, 0xf1, 0x2f, 0x58, 0xd0, 0xa8, 0xb4, 0xeb, 0x8f, 0xfd, 0x59, 0xf4, 0x25, 0x95, 0x06, 0x50,
+	0xbf, 0x90, 0x1d, 0xba, 0xf5, 0xd1, 0xf6, 0xc6, 0xfc, 0xce, 0xb0, 0x9f, 0x40, 0xd5, 0x77, 0x47,
+	0xd6, 0x4a, 0x8f, 0xaf, 0xf8, 0xee, 0x28, 0x51, 0x82, 0x22, 0x1e, 0xbf, 0x59, 0x8a, 0xc4, 0xb6,
+	0x2a, 0x1e, 0xbf, 0x49, 0x44, 0xd4, 0x1e, 0xd4, 0x92, 0x7f, 0xc6, 0x43, 0x1e, 0xdd, 0x67, 0x32,
+	0xa3, 0x6f, 0x6b, 0x5d, 0xdf, 0x60, 0x19, 0xc3, 0x97, 0x4c, 0xcd, 0x23, 0xc8, 0x7f, 0xf3, 0xde,
+	0x24, 0xfb, 0x50, 0x48, 0x77, 0xcd, 0x98, 0x50, 0x7f, 0x0c, 0xf5, 0x77, 0xb6, 0xeb, 0x8c, 0x9c,
+	0x68, 0x2e, 0x6d, 0xee, 0x43, 0xe1, 0x1a, 0x11, 0x21, 0x57, 0x62, 0x31, 0xa1, 0xbe, 0x81, 0x3a,
+	0xf3, 0x5d, 0xae, 0x85, 0xa1, 0x33, 0xf6, 0x44, 0x1b, 0xbd, 0x2b, 0x58, 0x02, 0xdb, 0x81, 0xef,
+	0x2e, 0x5a, 0x23, 0xfe, 0xab, 0xdf, 0x02, 0xa0, 0x86, 0x2f, 0x19, 0xd9, 0x9f, 0xa1, 0x2c, 0x4e,
+	0x87, 0xef, 0xf2, 0x3b, 0x34, 0xee, 0x43, 0x01, 0x1d, 0x40, 0x7d, 0xd8, 0x14, 0x63, 0x82, 0x1c,
+	0x43, 0x65, 0xca, 0x83, 0x89, 0x23, 0xcf, 0x75, 0x5e, 0xf0, 0xd2, 0x10, 0x9e, 0x0d, 0x24, 0x2d,
+	0x97, 0x5f, 0x73, 0x57, 0xb4, 0xa3, 0x02, 0x2b, 0x23, 0xd2, 0x45, 0x40, 0xd5, 0xa1, 0xde, 0xf5,
+	0x87, 0x57, 0xfe, 0x2c, 0xfa, 0x2f, 0x4e, 0x67, 0xb6, 0xdf, 0x6c, 0xad, 0xf4, 0x9b, 0x7f, 0xe4,
+	0xa0, 0x26, 0x75, 0xdd, 0x97, 0x9e, 0xbb, 0xf4, 0xe0, 0xcd, 0xe6, 0xfa, 0xc3, 0x2b, 0x3e, 0x92,
+	0x23, 0x89, 0xa4, 0xb0, 0xf6, 0xe2, 0x3f, 0x6b, 0xe6, 0x45, 0x8e, 0x2b, 0x5b, 0x6b, 0x25, 0xc6,
+	0x06, 0x08, 0xe1, 0x74, 0x20, 0x0c, 0x5e, 0xda, 0x8e, 0x3b, 0x0b, 0xc4, 0x0d, 0x82, 0xf1, 0x56,
+	0x11, 0xec, 0x48, 0x8c, 0xfc, 0x3f, 0x54, 0x9c, 0xe9, 0x52, 0x24, 0xbe, 0x33, 0xc0, 0x99, 0x2e,
+	0x04, 0x8e, 0xa0, 0x14, 0xf0, 0x28, 0x98, 0x63, 0x77, 0x2f, 0x0a, 0x23, 0x45, 0x41, 0x6b, 0x91,
+	0xfa, 0x01, 0x14, 0x6d, 0x16, 0x7d, 0xf4, 0x03, 0xe7, 0x4f, 0x3c, 0x49, 0xd8, 0x7a, 0x3b, 0x3c,
+	0x84, 0x1d, 0x7b, 0x18, 0x25, 0x93, 0x50, 0x99, 0x49, 0x0a, 0x53, 0x1b, 0xf0, 0xd0, 0x9f, 0x05,
+	0x43, 0x2e, 0x0b, 0x60, 0x41, 0xab, 0x14, 0xf6, 0x52, 0x9a, 0xc3, 0xa9, 0xef, 0x85, 0x1c, 0xcb,
+	0xc8, 0x76, 0x5d, 0xff, 0x86, 0x27, 0x75, 0x9c, 0x90, 0x68, 0x22, 0xe0, 0x76, 0xb8, 0x34, 0x11,
+	0x53, 0xea, 0x2e, 0xd4, 0xce, 0xf8, 0xdc, 0xe0, 0xc9, 0x76, 0xaa, 0x7f, 0xcd, 0xe1, 0xc1, 0x39,
+	0x43, 0x2f, 0xaf, 0xa2, 0x79, 0xe2, 0xe5, 0x55, 0x34, 0x17, 0xc8, 0xa2, 0x5d, 0xe2, 0x2f, 0x22,
+	0xb3, 0x30, 0x71, 0x0d, 0x7f, 0x11, 0xb1, 0xdd, 0xb1, 0x48, 0x75, 0x99, 0xe1, 0x2f, 0xa9, 0x42,
+	0xce, 0x93, 0x33, 0x52, 0xce, 0x43, 0x8a, 0xcb, 0x6b, 0x29, 0x27, 0xa4, 0x87, 0xc1, 0xb5, 0xbc,
+	0x88, 0xf0, 0x17, 0xf9, 0x9f, 0x1a, 0xa5, 0x98, 0xff, 0x09, 0xa9, 0x79, 0xa3, 0x1c, 0x53, 0x73,
+	0xf5, 0x19, 0xec, 0xc4, 0xae, 0x92, 0xaf, 0x61, 0xfb, 0x8a, 0xcf, 0x93, 0x6b, 0x69, 0x37, 0x7d,
+	0x2d, 0x7d, 0xf3, 0xfe, 0x8c, 0x09, 0xa6, 0xfa, 0x1b, 0x28, 0xd3, 0x20, 0xf0, 0x03, 0x71, 0x75,
+	0xff, 0x6c, 0x11, 0x3e, 0x06, 0x54, 0xcf, 0x5e, 0x65, 0x42, 0x8c, 0x09, 0x76, 0x92, 0x97, 0x9f,
+	0xfe, 0x73, 0x1b, 0x2a, 0x29, 0x9c, 0x7c, 0x05, 0x0d, 0xca, 0x58, 0x9f, 0x59, 0x8c, 0x6a, 0x46,
+	0xbf, 0x67, 0x0d, 0x7a, 0xc6, 0x05, 0x6d, 0xe9, 0x1d, 0x9d, 0xb6, 0x95, 0xff, 0x23, 0x55, 0x28,
+	0xe9, 0x3d, 0x93, 0xb2, 0x9e, 0xd6, 0x55, 0x72, 0xa4, 0x09, 0x87, 0x8c, 0x5e, 0xf4, 0x0d, 0xdd,
+	0xec, 0xb3, 0xef, 0xac, 0x41, 0x4f, 0x7b, 0xa7, 0xe9, 0x5d, 0xed, 0x6d, 0x97, 0x2a, 0x5b, 0xa4,
+	0x02, 0x45, 0x53, 0x3f, 0xa7, 0xfd, 0x81, 0xa9, 0xe4, 0x49, 0x0d, 0xca, 0x2d, 0xad, 0xd7, 0xa2,
+	0xdd, 0x2e, 0x6d, 0x2b, 0xdb, 0xe4, 0x21, 0x3c, 0xd0, 0x7b, 0xef, 0xb4, 0xae, 0xde, 0xb6, 0x5a,
+	0x8c, 0xb6, 0x69, 0xcf, 0xd4, 0xb5, 0xae, 0xa1, 0x14, 0x08, 0x81, 0xfa, 0xc0, 0xa0, 0xcc, 0xea,
+	0xf5, 0x4d, 0xab, 0xd3, 0x1f, 0xf4, 0xda, 0xca, 0x0e, 0xd9, 0x85, 0x8a, 0xc0, 0xe8, 0x07, 0xdd,
+	0x30, 0x0d, 0xa5, 0x48, 0x1e, 0xc0, 0x6e, 0xb2, 0x5a, 0x30, 0xf4, 0xb6, 0x52, 0x22, 0x7b, 0x50,
+	0x7b, 0x4f, 0xb5, 0x33, 0xeb, 0x42, 0x33, 0x8c, 0xf7, 0x7d, 0xd6, 0x56, 0xca, 0x69, 0x39, 0x83,
+	0x1a, 0x86, 0xde, 0xef, 0x29, 0x40, 0x0e, 0x60, 0x4f, 0x12, 0x29, 0x23, 0x15, 0xd2, 0x80, 0x7d,
+	0x46, 0x3b, 0x8c, 0x1a, 0xa7, 0x96, 0xd9, 0x3f, 0xa3, 0x3d, 0x8b, 0xd1, 0x81, 0x41, 0xdb, 0x4a,
+	0x15, 0x15, 0x27, 0x5a, 0x04, 0x47, 0xa9, 0x21, 0x14, 0x0b, 0xd1, 0x0f, 0x17, 0x3a, 0xa3, 0x6d,
+	0xa5, 0x8e, 0x8e, 0x6b, 0xad, 0x56, 0x7f, 0xd0, 0x33, 0xad, 0x6e, 0xbf, 0x75, 0x46, 0xdb, 0xca,
+	0x2e, 0x9a, 0x32, 0xfb, 0x7d, 0xeb, 0x5c, 0xeb, 0x7d, 0x67, 0x69, 0xa6, 0x49, 0xcf, 0x2f, 0x4c,
+	0x43, 0x51, 0x88, 0x02, 0x55, 0xa6, 0x99, 0xd4, 0xea, 0xea, 0xe7, 0xba, 0x49, 0xdb, 0xca, 0x1e,
+	0x22, 0xe7, 0x1d, 0xcd, 0x62, 0xf4, 0xdb, 0x81, 0x50, 0x47, 0xc8, 0x3e, 0x28, 0x89, 0x51, 0xe4,
+	0xb4, 0xfa, 0x6d, 0xaa, 0x3c, 0x20, 0x47, 0x70, 0x90, 0x41, 0x4f, 0xb5, 0x6e, 0x97, 0xf6, 0x4e,
+	0xa8, 0xb2, 0x8f, 0x49, 0x42, 0x88, 0xf6, 0x30, 0xfb, 0x6d, 0xe5, 0x00, 0x83, 0x47, 0x00, 0x63,
+	0x4c, 0xc0, 0x43, 0x54, 0xbb, 0x04, 0x59, 0x5f, 0xec, 0xc6, 0x43, 0xf4, 0x9d, 0xf5, 0xbb, 0x34,
+	0x95, 0x8f, 0x06, 0x9a, 0xd2, 0xda, 0xe7, 0x7a, 0xcf, 0x32, 0x68, 0x8b, 0x51, 0x73, 0xe9, 0xdb,
+	0x11, 0xa6, 0x2a, 0xf1, 0x22, 0x2d, 0xa2, 0x34, 0x91, 0xd3, 0xea, 0xea, 0xb4, 0x67, 0x5a, 0x2d,
+	0xca, 0x52, 0x6b, 0x1e, 0xbd, 0xf8, 0x5b, 0x09, 0xea, 0x78, 0x88, 0xf1, 0x91, 0x27, 0xc7, 0x96,
+	0x37, 0x50, 0x4a, 0x1e, 0x50, 0xa4, 0x91, 0x2e, 0xd2, 0xf4, 0x2b, 0xae, 0xd9, 0x5c, 0xe7, 0x2c,
+	0x1e, 0x5c, 0xbf, 0x80, 0x42, 0xd7, 0x1f, 0x3b, 0x1e, 0xc9, 0xd4, 0x78, 0xea, 0x2d, 0xd9, 0xdc,
+	0xf4, 0xd0, 0x22, 0x2f, 0xa1, 0xc8, 0xe2, 0x71, 0x92, 0x6c, 0xe2, 0x6f, 0x5e, 0xf4, 0x1a, 0x2a,
+	0xe2, 0x1e, 0xb5, 0x23, 0x8e, 0x97, 0xed, 0xca, 0x49, 0x34, 0xb3, 0x7e, 0xae, 0xdc, 0xb8, 0xbf,
+	0x82, 0x9d, 0x78, 0x20, 0xda, 0x6c, 0x2f, 0x13, 0x7c, 0x66, 0x72, 0x3a, 0x85, 0x7a, 0x3c, 0xec,
+	0x2c, 0xa6, 0x96, 0x8c, 0x99, 0xec, 0x40, 0xd4, 0xdc, 0xc8, 0x93, 0x9a, 0x5e, 0x41, 0x15, 0x47,
+	0x5d, 0x69, 0x32, 0x5c, 0xf7, 0x7f, 0xd3, 0xc4, 0x8b, 0x2b, 0xc8, 0x29, 0xd4, 0xe4, 0x88, 0x28,
+	0x33, 0xf1, 0x78, 0x83, 0xe4, 0x72, 0x1a, 0x6d, 0xae, 0x6c, 0x65, 0x6a, 0xb8, 0xfc, 0x1d, 0xec,
+	0xc5, 0xb4, 0xe6, 0xba, 0xb7, 0x3b, 0x72, 0xfb, 0xfa, 0x57, 0x50, 0x3c, 0xe1, 0xd1, 0x19, 0x9f,
+	0x87, 0xe4, 0x28, 0x2d, 0x94, 0xe9, 0xea, 0x4d, 0xb2, 0xce, 0x22, 0xa7, 0x50, 0x5e, 0xdc, 0x20,
+	0xe4, 0xab, 0xb4, 0xc0, 0xea, 0x95, 0xd5, 0x7c, 0x7c, 0x0b, 0x57, 0x5e, 0x3b, 0xbf, 0x85, 0xb2,
+	0x78, 0xae, 0xce, 0xcf, 0x3b, 0x1a, 0x79, 0x94, 0x96, 0x5d, 0x79, 0xc8, 0x6e, 0xae, 0xa2, 0x5f,
+	0x03, 0xc4, 0x0f, 0x55, 0x7c, 0x76, 0xde, 0x53, 0x44, 0x2b, 0xaf, 0xda, 0xd7, 0x50, 0x69, 0xf9,
+	0xde, 0xa5, 0x13, 0x4c, 0xc4, 0xda, 0xfd, 0x55, 0x51, 0x7c, 0xd1, 0x35, 0x0f, 0x56, 0x3c, 0x92,
+	0x99, 0x7b, 0x0d, 0x95, 0xb6, 0x13, 0xe2, 0x5b, 0xf2, 0xf3, 0xd7, 0x76, 0xe0, 0x21, 0xe3, 0x63,
+	0xee, 0xf1, 0xc0, 0x8e, 0x38, 0xcb, 0xbc, 0x37, 0x3f, 0x4b, 0xcf, 0x2f, 0xa1, 0x7a, 0xc2, 0xa3,
+	0x25, 0xbd, 0x16, 0xfc, 0xe6, 0x75, 0x2f, 0xfe, 0x52, 0x80, 0x82, 0x36, 0x9a, 0x38, 0x1e, 0xe9,
+	0x60, 0x25, 0x86, 0x3c, 0x5a, 0x1c, 0x86, 0xa3, 0x4d, 0x05, 0x2f, 0x44, 0xee, 0x3c, 0x0b, 0x6d,
+	0x50, 0xb0, 0xb2, 0x53, 0x8f, 0xa9, 0x30, 0xdb, 0x41, 0x52, 0x2f, 0xb2, 0xdb, 0xcf, 0xc5, 0x45,
+	0x52, 0xcd, 0x29, 0x3d, 0xe4, 0xc9, 0xaa, 0x9a, 0xcf, 0x39, 0x1f, 0x27, 0x40, 0xd6, 0x34, 0xde,
+	0xe1, 0xd9, 0xed, 0x8a, 0xde, 0x00, 0xc4, 0x73, 0x3c, 0x0e, 0xce, 0xd9, 0x96, 0x91, 0x9d, 0xf1,
+	0x9b, 0x87, 0xab, 0xbc, 0xa5, 0x06, 0xc6, 0x27, 0xfe, 0x35, 0xff, 0xc1, 0x1a, 0x7e, 0x0f, 0xb5,
+	0x24, 0xc9, 0x72, 0x7c, 0xbf, 0x2d, 0x8e, 0x83, 0x35, 0x86, 0x90, 0x6f, 0x01, 0x9c, 0xf0, 0x48,
+	0x4e, 0xcd, 0x59, 0x17, 0xb2, 0x63, 0x79, 0xf3, 0x68, 0x03, 0x4f, 0x7a, 0x41, 0xa1, 0xda, 0x72,
+	0xb9, 0x1d, 0xfc, 0x6f, 0x6a, 0xbe, 0xdf, 0x11, 0x9c, 0x97, 0xff, 0x19, 0x00, 0x21, 0xfa, 0x68,
+	0x26, 0xc2, 0x14, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// AuthenticationClient is the client API for Authentication service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthenticationClient interface {
+	Register(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*RegisterStatus, error)
+	Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error)
+	Refresh(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error)
+	ValidateJWT(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*ValidityStatus, error)
+	Logout(ctx context.Context, in *Session, opts ...grpc.CallOption) (*LogoutStatus, error)
+	ChangePassword(ctx context.Context, in *PasswordChange, opts ...grpc.CallOption) (*PasswordStatus, error)
+	ListSessions(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*SessionList, error)
+	RevokeSession(ctx context.Context, in *SessionRevocation, opts ...grpc.CallOption) (*RevokeStatus, error)
+	RevokeAllSessions(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*RevokeStatus, error)
+	GetKeys(ctx context.Context, in *KeySetRequest, opts ...grpc.CallOption) (*KeySet, error)
+	Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeResponse, error)
+	VerifyMFA(ctx context.Context, in *MFAVerification, opts ...grpc.CallOption) (*Session, error)
+	EnrollTOTP(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error)
+	DisableTOTP(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error)
+	RegenerateRecoveryCodes(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error)
+	GetMFAStatus(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*MFAStatus, error)
+}
+
+type authenticationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAuthenticationClient(cc grpc.ClientConnInterface) AuthenticationClient {
+	return &authenticationClient{cc}
+}
+
+func (c *authenticationClient) Register(ctx context.Context, in *Registration, opts ...grpc.CallOption) (*RegisterStatus, error) {
+	out := new(RegisterStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Register", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) Login(ctx context.Context, in *Credentials, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Login", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) Refresh(ctx context.Context, in *Session, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Refresh", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) ValidateJWT(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*ValidityStatus, error) {
+	out := new(ValidityStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/ValidateJWT", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) Logout(ctx context.Context, in *Session, opts ...grpc.CallOption) (*LogoutStatus, error) {
+	out := new(LogoutStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) ChangePassword(ctx context.Context, in *PasswordChange, opts ...grpc.CallOption) (*PasswordStatus, error) {
+	out := new(PasswordStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/ChangePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) ListSessions(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/ListSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) RevokeSession(ctx context.Context, in *SessionRevocation, opts ...grpc.CallOption) (*RevokeStatus, error) {
+	out := new(RevokeStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/RevokeSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) RevokeAllSessions(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*RevokeStatus, error) {
+	out := new(RevokeStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/RevokeAllSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) GetKeys(ctx context.Context, in *KeySetRequest, opts ...grpc.CallOption) (*KeySet, error) {
+	out := new(KeySet)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/GetKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) Authorize(ctx context.Context, in *AuthorizeRequest, opts ...grpc.CallOption) (*AuthorizeResponse, error) {
+	out := new(AuthorizeResponse)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/Authorize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) VerifyMFA(ctx context.Context, in *MFAVerification, opts ...grpc.CallOption) (*Session, error) {
+	out := new(Session)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/VerifyMFA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) EnrollTOTP(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*TOTPEnrollment, error) {
+	out := new(TOTPEnrollment)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/EnrollTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) ConfirmTOTP(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error) {
+	out := new(MFAStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/ConfirmTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) DisableTOTP(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error) {
+	out := new(MFAStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/DisableTOTP", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) RegenerateRecoveryCodes(ctx context.Context, in *TOTPCode, opts ...grpc.CallOption) (*MFAStatus, error) {
+	out := new(MFAStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/RegenerateRecoveryCodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authenticationClient) GetMFAStatus(ctx context.Context, in *JWT, opts ...grpc.CallOption) (*MFAStatus, error) {
+	out := new(MFAStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Authentication/GetMFAStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthenticationServer is the server API for Authentication service.
+type AuthenticationServer interface {
+	Register(context.Context, *Registration) (*RegisterStatus, error)
+	Login(context.Context, *Credentials) (*Session, error)
+	Refresh(context.Context, *Session) (*Session, error)
+	ValidateJWT(context.Context, *JWT) (*ValidityStatus, error)
+	Logout(context.Context, *Session) (*LogoutStatus, error)
+	ChangePassword(context.Context, *PasswordChange) (*PasswordStatus, error)
+	ListSessions(context.Context, *JWT) (*SessionList, error)
+	RevokeSession(context.Context, *SessionRevocation) (*RevokeStatus, error)
+	RevokeAllSessions(context.Context, *JWT) (*RevokeStatus, error)
+	GetKeys(context.Context, *KeySetRequest) (*KeySet, error)
+	Authorize(context.Context, *AuthorizeRequest) (*AuthorizeResponse, error)
+	VerifyMFA(context.Context, *MFAVerification) (*Session, error)
+	EnrollTOTP(context.Context, *JWT) (*TOTPEnrollment, error)
+	ConfirmTOTP(context.Context, *TOTPCode) (*MFAStatus, error)
+	DisableTOTP(context.Context, *TOTPCode) (*MFAStatus, error)
+	RegenerateRecoveryCodes(context.Context, *TOTPCode) (*MFAStatus, error)
+	GetMFAStatus(context.Context, *JWT) (*MFAStatus, error)
+}
+
+// UnimplementedAuthenticationServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthenticationServer struct {
+}
+
+func (*UnimplementedAuthenticationServer) Register(ctx context.Context, req *Registration) (*RegisterStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+func (*UnimplementedAuthenticationServer) Login(ctx context.Context, req *Credentials) (*Session, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Login not implemented")
 }
-func (*UnimplementedAuthenticationServer) Refresh(ctx context.Context, req *Session) (*Session, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+func (*UnimplementedAuthenticationServer) Refresh(ctx context.Context, req *Session) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (*UnimplementedAuthenticationServer) ValidateJWT(ctx context.Context, req *JWT) (*ValidityStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateJWT not implemented")
+}
+func (*UnimplementedAuthenticationServer) Logout(ctx context.Context, req *Session) (*LogoutStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedAuthenticationServer) ChangePassword(ctx context.Context, req *PasswordChange) (*PasswordStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (*UnimplementedAuthenticationServer) ListSessions(ctx context.Context, req *JWT) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSessions not implemented")
+}
+func (*UnimplementedAuthenticationServer) RevokeSession(ctx context.Context, req *SessionRevocation) (*RevokeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeSession not implemented")
+}
+func (*UnimplementedAuthenticationServer) RevokeAllSessions(ctx context.Context, req *JWT) (*RevokeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAllSessions not implemented")
+}
+func (*UnimplementedAuthenticationServer) GetKeys(ctx context.Context, req *KeySetRequest) (*KeySet, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeys not implemented")
+}
+func (*UnimplementedAuthenticationServer) Authorize(ctx context.Context, req *AuthorizeRequest) (*AuthorizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Authorize not implemented")
+}
+func (*UnimplementedAuthenticationServer) VerifyMFA(ctx context.Context, req *MFAVerification) (*Session, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMFA not implemented")
+}
+func (*UnimplementedAuthenticationServer) EnrollTOTP(ctx context.Context, req *JWT) (*TOTPEnrollment, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollTOTP not implemented")
+}
+func (*UnimplementedAuthenticationServer) ConfirmTOTP(ctx context.Context, req *TOTPCode) (*MFAStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmTOTP not implemented")
+}
+func (*UnimplementedAuthenticationServer) DisableTOTP(ctx context.Context, req *TOTPCode) (*MFAStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableTOTP not implemented")
+}
+func (*UnimplementedAuthenticationServer) RegenerateRecoveryCodes(ctx context.Context, req *TOTPCode) (*MFAStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegenerateRecoveryCodes not implemented")
+}
+func (*UnimplementedAuthenticationServer) GetMFAStatus(ctx context.Context, req *JWT) (*MFAStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMFAStatus not implemented")
+}
+
+func RegisterAuthenticationServer(s *grpc.Server, srv AuthenticationServer) {
+	s.RegisterService(&_Authentication_serviceDesc, srv)
+}
+
+func _Authentication_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Registration)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).Register(ctx, req.(*Registration))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Credentials)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).Login(ctx, req.(*Credentials))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Session)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).Refresh(ctx, req.(*Session))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_ValidateJWT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWT)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).ValidateJWT(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/ValidateJWT",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).ValidateJWT(ctx, req.(*JWT))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Session)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).Logout(ctx, req.(*Session))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordChange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).ChangePassword(ctx, req.(*PasswordChange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWT)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).ListSessions(ctx, req.(*JWT))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionRevocation)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).RevokeSession(ctx, req.(*SessionRevocation))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_RevokeAllSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWT)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).RevokeAllSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/RevokeAllSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).RevokeAllSessions(ctx, req.(*JWT))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_GetKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeySetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).GetKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/GetKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).GetKeys(ctx, req.(*KeySetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_Authorize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).Authorize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/Authorize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).Authorize(ctx, req.(*AuthorizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_VerifyMFA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MFAVerification)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).VerifyMFA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/VerifyMFA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).VerifyMFA(ctx, req.(*MFAVerification))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_EnrollTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWT)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).EnrollTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/EnrollTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).EnrollTOTP(ctx, req.(*JWT))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_ConfirmTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TOTPCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).ConfirmTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/ConfirmTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).ConfirmTOTP(ctx, req.(*TOTPCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_DisableTOTP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TOTPCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).DisableTOTP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/DisableTOTP",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).DisableTOTP(ctx, req.(*TOTPCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_RegenerateRecoveryCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TOTPCode)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).RegenerateRecoveryCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/RegenerateRecoveryCodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).RegenerateRecoveryCodes(ctx, req.(*TOTPCode))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Authentication_GetMFAStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JWT)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthenticationServer).GetMFAStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Authentication/GetMFAStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthenticationServer).GetMFAStatus(ctx, req.(*JWT))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedAuthenticationServer) ValidateJWT(ctx context.Context, req *JWT) (*ValidityStatus, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ValidateJWT not implemented")
+
+var _Authentication_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.auth.Authentication",
+	HandlerType: (*AuthenticationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Authentication_Register_Handler,
+		},
+		{
+			MethodName: "Login",
+			Handler:    _Authentication_Login_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _Authentication_Refresh_Handler,
+		},
+		{
+			MethodName: "ValidateJWT",
+			Handler:    _Authentication_ValidateJWT_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _Authentication_Logout_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _Authentication_ChangePassword_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _Authentication_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _Authentication_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllSessions",
+			Handler:    _Authentication_RevokeAllSessions_Handler,
+		},
+		{
+			MethodName: "GetKeys",
+			Handler:    _Authentication_GetKeys_Handler,
+		},
+		{
+			MethodName: "Authorize",
+			Handler:    _Authentication_Authorize_Handler,
+		},
+		{
+			MethodName: "VerifyMFA",
+			Handler:    _Authentication_VerifyMFA_Handler,
+		},
+		{
+			MethodName: "EnrollTOTP",
+			Handler:    _Authentication_EnrollTOTP_Handler,
+		},
+		{
+			MethodName: "ConfirmTOTP",
+			Handler:    _Authentication_ConfirmTOTP_Handler,
+		},
+		{
+			MethodName: "DisableTOTP",
+			Handler:    _Authentication_DisableTOTP_Handler,
+		},
+		{
+			MethodName: "RegenerateRecoveryCodes",
+			Handler:    _Authentication_RegenerateRecoveryCodes_Handler,
+		},
+		{
+			MethodName: "GetMFAStatus",
+			Handler:    _Authentication_GetMFAStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "auth.proto",
 }
-func (*UnimplementedAuthenticationServer) Logout(ctx context.Context, req *Session) (*LogoutStatus, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AdminClient interface {
+	ResetPassword(ctx context.Context, in *PasswordReset, opts ...grpc.CallOption) (*PasswordStatus, error)
+	ListUserSessions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*SessionList, error)
+	RevokeUserSession(ctx context.Context, in *UserSessionRevocation, opts ...grpc.CallOption) (*RevokeStatus, error)
+	RevokeUserSessions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*RevokeStatus, error)
+	AssignRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleStatus, error)
+	RemoveRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleStatus, error)
+	ListUserRoles(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserRoles, error)
+	GetLockout(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*LockoutStatus, error)
+	ClearLockout(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*LockoutStatus, error)
 }
 
-func RegisterAuthenticationServer(s *grpc.Server, srv AuthenticationServer) {
-	s.RegisterService(&_Authentication_serviceDesc, srv)
+type adminClient struct {
+	cc grpc.ClientConnInterface
 }
 
-func _Authentication_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Credentials)
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ResetPassword(ctx context.Context, in *PasswordReset, opts ...grpc.CallOption) (*PasswordStatus, error) {
+	out := new(PasswordStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/ResetPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListUserSessions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*SessionList, error) {
+	out := new(SessionList)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/ListUserSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RevokeUserSession(ctx context.Context, in *UserSessionRevocation, opts ...grpc.CallOption) (*RevokeStatus, error) {
+	out := new(RevokeStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/RevokeUserSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RevokeUserSessions(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*RevokeStatus, error) {
+	out := new(RevokeStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/RevokeUserSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AssignRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleStatus, error) {
+	out := new(RoleStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/AssignRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveRole(ctx context.Context, in *RoleAssignment, opts ...grpc.CallOption) (*RoleStatus, error) {
+	out := new(RoleStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/RemoveRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListUserRoles(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserRoles, error) {
+	out := new(UserRoles)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/ListUserRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetLockout(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*LockoutStatus, error) {
+	out := new(LockoutStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/GetLockout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ClearLockout(ctx context.Context, in *LockoutRequest, opts ...grpc.CallOption) (*LockoutStatus, error) {
+	out := new(LockoutStatus)
+	err := c.cc.Invoke(ctx, "/proto.auth.Admin/ClearLockout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+type AdminServer interface {
+	ResetPassword(context.Context, *PasswordReset) (*PasswordStatus, error)
+	ListUserSessions(context.Context, *UserRequest) (*SessionList, error)
+	RevokeUserSession(context.Context, *UserSessionRevocation) (*RevokeStatus, error)
+	RevokeUserSessions(context.Context, *UserRequest) (*RevokeStatus, error)
+	AssignRole(context.Context, *RoleAssignment) (*RoleStatus, error)
+	RemoveRole(context.Context, *RoleAssignment) (*RoleStatus, error)
+	ListUserRoles(context.Context, *UserRequest) (*UserRoles, error)
+	GetLockout(context.Context, *LockoutRequest) (*LockoutStatus, error)
+	ClearLockout(context.Context, *LockoutRequest) (*LockoutStatus, error)
+}
+
+// UnimplementedAdminServer can be embedded to have forward compatible implementations.
+type UnimplementedAdminServer struct {
+}
+
+func (*UnimplementedAdminServer) ResetPassword(ctx context.Context, req *PasswordReset) (*PasswordStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (*UnimplementedAdminServer) ListUserSessions(ctx context.Context, req *UserRequest) (*SessionList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserSessions not implemented")
+}
+func (*UnimplementedAdminServer) RevokeUserSession(ctx context.Context, req *UserSessionRevocation) (*RevokeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserSession not implemented")
+}
+func (*UnimplementedAdminServer) RevokeUserSessions(ctx context.Context, req *UserRequest) (*RevokeStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeUserSessions not implemented")
+}
+func (*UnimplementedAdminServer) AssignRole(ctx context.Context, req *RoleAssignment) (*RoleStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (*UnimplementedAdminServer) RemoveRole(ctx context.Context, req *RoleAssignment) (*RoleStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRole not implemented")
+}
+func (*UnimplementedAdminServer) ListUserRoles(ctx context.Context, req *UserRequest) (*UserRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserRoles not implemented")
+}
+func (*UnimplementedAdminServer) GetLockout(ctx context.Context, req *LockoutRequest) (*LockoutStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLockout not implemented")
+}
+func (*UnimplementedAdminServer) ClearLockout(ctx context.Context, req *LockoutRequest) (*LockoutStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearLockout not implemented")
+}
+
+func RegisterAdminServer(s *grpc.Server, srv AdminServer) {
+	s.RegisterService(&_Admin_serviceDesc, srv)
+}
+
+func _Admin_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordReset)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticationServer).Login(ctx, in)
+		return srv.(AdminServer).ResetPassword(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.auth.Authentication/Login",
+		FullMethod: "/proto.auth.Admin/ResetPassword",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticationServer).Login(ctx, req.(*Credentials))
+		return srv.(AdminServer).ResetPassword(ctx, req.(*PasswordReset))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Authentication_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Session)
+func _Admin_ListUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticationServer).Refresh(ctx, in)
+		return srv.(AdminServer).ListUserSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.auth.Authentication/Refresh",
+		FullMethod: "/proto.auth.Admin/ListUserSessions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticationServer).Refresh(ctx, req.(*Session))
+		return srv.(AdminServer).ListUserSessions(ctx, req.(*UserRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Authentication_ValidateJWT_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(JWT)
+func _Admin_RevokeUserSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserSessionRevocation)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticationServer).ValidateJWT(ctx, in)
+		return srv.(AdminServer).RevokeUserSession(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.auth.Authentication/ValidateJWT",
+		FullMethod: "/proto.auth.Admin/RevokeUserSession",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticationServer).ValidateJWT(ctx, req.(*JWT))
+		return srv.(AdminServer).RevokeUserSession(ctx, req.(*UserSessionRevocation))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Authentication_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Session)
+func _Admin_RevokeUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthenticationServer).Logout(ctx, in)
+		return srv.(AdminServer).RevokeUserSessions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/proto.auth.Authentication/Logout",
+		FullMethod: "/proto.auth.Admin/RevokeUserSessions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthenticationServer).Logout(ctx, req.(*Session))
+		return srv.(AdminServer).RevokeUserSessions(ctx, req.(*UserRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Authentication_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "proto.auth.Authentication",
-	HandlerType: (*AuthenticationServer)(nil),
+func _Admin_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleAssignment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Admin/AssignRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AssignRole(ctx, req.(*RoleAssignment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleAssignment)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Admin/RemoveRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveRole(ctx, req.(*RoleAssignment))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListUserRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListUserRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Admin/ListUserRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListUserRoles(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetLockout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetLockout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Admin/GetLockout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetLockout(ctx, req.(*LockoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ClearLockout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ClearLockout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.auth.Admin/ClearLockout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ClearLockout(ctx, req.(*LockoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Admin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.auth.Admin",
+	HandlerType: (*AdminServer)(nil),
 	Methods: []grpc.MethodDesc{
 		{
-			MethodName: "Login",
-			Handler:    _Authentication_Login_Handler,
+			MethodName: "ResetPassword",
+			Handler:    _Admin_ResetPassword_Handler,
 		},
 		{
-			MethodName: "Refresh",
-			Handler:    _Authentication_Refresh_Handler,
+			MethodName: "ListUserSessions",
+			Handler:    _Admin_ListUserSessions_Handler,
 		},
 		{
-			MethodName: "ValidateJWT",
-			Handler:    _Authentication_ValidateJWT_Handler,
+			MethodName: "RevokeUserSession",
+			Handler:    _Admin_RevokeUserSession_Handler,
 		},
 		{
-			MethodName: "Logout",
-			Handler:    _Authentication_Logout_Handler,
+			MethodName: "RevokeUserSessions",
+			Handler:    _Admin_RevokeUserSessions_Handler,
+		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _Admin_AssignRole_Handler,
+		},
+		{
+			MethodName: "RemoveRole",
+			Handler:    _Admin_RemoveRole_Handler,
+		},
+		{
+			MethodName: "ListUserRoles",
+			Handler:    _Admin_ListUserRoles_Handler,
+		},
+		{
+			MethodName: "GetLockout",
+			Handler:    _Admin_GetLockout_Handler,
+		},
+		{
+			MethodName: "ClearLockout",
+			Handler:    _Admin_ClearLockout_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -3,5 +3,5 @@ package proto_auth
 import grpc "google.golang.org/grpc"
 
 type Service interface {
-	Register(*grpc.Server)
+	RegisterServer(*grpc.Server)
 }
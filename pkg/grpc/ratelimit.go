@@ -0,0 +1,91 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"net"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RateLimits are the token buckets calls are limited by, every method has a bucket per peer
+type RateLimits struct {
+	// Default limit of methods without their own limit
+	Default ratelimit.Limit
+	// Methods holds the limits of methods keyed by their name (Login) or full method
+	// (/auth.Authentication/Login), names are matched case insensitively
+	Methods map[string]ratelimit.Limit
+}
+
+// limit will get the limit of a full method name, a limit set for the full method takes
+// precedence over one set for its name
+func (rl RateLimits) limit(fullMethod string) ratelimit.Limit {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	for _, key := range []string{fullMethod, name} {
+		for method, limit := range rl.Methods {
+			if strings.EqualFold(method, key) {
+				return limit
+			}
+		}
+	}
+
+	return rl.Default
+}
+
+// peerAddr will get the host of the peer making a call
+func peerAddr(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
+
+// RateLimitInterceptor will limit unary calls per method and peer address. Calls over the
+// limit fail with ResourceExhausted along with RetryInfo saying when to retry. Calls are let
+// through when the limiter fails so an unavailable limiter doesn't stop logins
+func RateLimitInterceptor(limiter ratelimit.Limiter, limits RateLimits,
+	lg *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		limit := limits.limit(info.FullMethod)
+		if limit.Unlimited() {
+			return handler(ctx, req)
+		}
+
+		allowed, wait, err := limiter.Allow(info.FullMethod+"|"+peerAddr(ctx), limit)
+		if err != nil {
+			lg.Printf("ERROR: failed to rate limit: %s: %s\n", info.FullMethod, err.Error())
+			return handler(ctx, req)
+		}
+
+		if allowed {
+			return handler(ctx, req)
+		}
+
+		st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for: %s",
+			info.FullMethod)
+		if detailed, err := st.WithDetails(
+			&proto.ErrorInfo{Reason: proto.ErrorReason_RATE_LIMITED},
+			&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(wait)},
+		); err == nil {
+			st = detailed
+		}
+
+		return nil, st.Err()
+	}
+}
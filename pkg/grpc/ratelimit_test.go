@@ -0,0 +1,79 @@
+package grpc_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	authgrpc "github.com/joshturge-io/auth/pkg/grpc"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// peerContext will create a context for a call made by a peer address
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 50000},
+	})
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	interceptor := authgrpc.RateLimitInterceptor(ratelimit.NewMemoryLimiter(),
+		authgrpc.RateLimits{
+			Methods: map[string]ratelimit.Limit{"login": {Rate: 1, Burst: 1}},
+		}, log.New(ioutil.Discard, "", 0))
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+	login := &grpc.UnaryServerInfo{FullMethod: "/auth.Authentication/Login"}
+
+	if _, err := interceptor(peerContext("10.0.0.1"), nil, login, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := interceptor(peerContext("10.0.0.1"), nil, login, handler)
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted got: %v", err)
+	}
+
+	if len(st.Details()) != 2 {
+		t.Fatalf("expected error and retry info in the status details got: %v", st.Details())
+	}
+
+	errInfo, ok := st.Details()[0].(*proto.ErrorInfo)
+	if !ok || errInfo.GetReason() != proto.ErrorReason_RATE_LIMITED {
+		t.Errorf("expected ErrorInfo with reason RATE_LIMITED got: %v", st.Details()[0])
+	}
+
+	info, ok := st.Details()[1].(*errdetails.RetryInfo)
+	if !ok {
+		t.Fatalf("expected RetryInfo got: %T", st.Details()[1])
+	}
+
+	if delay, err := ptypes.Duration(info.GetRetryDelay()); err != nil || delay <= 0 {
+		t.Errorf("unexpected retry delay: %v: %v", info.GetRetryDelay(), err)
+	}
+
+	// every peer has its own bucket
+	if _, err = interceptor(peerContext("10.0.0.2"), nil, login, handler); err != nil {
+		t.Errorf("call from another peer should be allowed got: %v", err)
+	}
+
+	// methods without a limit fall back to the default, which is unlimited here
+	validate := &grpc.UnaryServerInfo{FullMethod: "/auth.Authentication/ValidateJWT"}
+	for i := 0; i < 5; i++ {
+		if _, err = interceptor(peerContext("10.0.0.1"), nil, validate, handler); err != nil {
+			t.Errorf("call without a limit should be allowed got: %v", err)
+		}
+	}
+}
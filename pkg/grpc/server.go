@@ -3,23 +3,36 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"log"
 	"net"
+	"time"
 
 	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+// HealthCheck checks the dependencies of the server, returns nil when they are healthy
+type HealthCheck func() error
+
 // Server is a grpc server
 type Server struct {
 	gs       *grpc.Server
+	health   *health.Server
 	listener net.Listener
 	serveErr error
+	quit     chan struct{}
 }
 
-// NewServer will create a new listener and server with registered services
-func NewServer(addr string, services ...proto.Service) (*Server, error) {
+// NewServer will create a new listener and server with registered services, opts are passed
+// to the underlying grpc server. The grpc.health.v1 and reflection services are registered
+// along with the services
+func NewServer(addr string, opts []grpc.ServerOption, services ...proto.Service) (*Server,
+	error) {
 	var (
-		srv = &Server{}
+		srv = &Server{health: health.NewServer(), quit: make(chan struct{})}
 		err error
 	)
 	srv.listener, err = net.Listen("tcp", addr)
@@ -27,15 +40,58 @@ func NewServer(addr string, services ...proto.Service) (*Server, error) {
 		return nil, fmt.Errorf("unable to listen to address: %s: %w", addr, err)
 	}
 
-	srv.gs = grpc.NewServer()
+	srv.gs = grpc.NewServer(opts...)
 
 	for _, service := range services {
-		service.Register(srv.gs)
+		service.RegisterServer(srv.gs)
 	}
 
+	healthpb.RegisterHealthServer(srv.gs, srv.health)
+	reflection.Register(srv.gs)
+
 	return srv, nil
 }
 
+// setServing will set the health of the server and every registered service
+func (s *Server) setServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+
+	s.health.SetServingStatus("", status)
+	for name := range s.gs.GetServiceInfo() {
+		s.health.SetServingStatus(name, status)
+	}
+}
+
+// WatchHealth will run check every interval until the server is closed, the server and its
+// services are reported as not serving while check fails
+func (s *Server) WatchHealth(check HealthCheck, interval time.Duration, lg *log.Logger) {
+	healthy := check() == nil
+	s.setServing(healthy)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				err := check()
+				if err != nil && healthy {
+					lg.Printf("ERROR: health check failed, not serving: %s\n", err.Error())
+				} else if err == nil && !healthy {
+					lg.Println("Health check passed, serving")
+				}
+				healthy = err == nil
+				s.setServing(healthy)
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+}
+
 // Serve will start serving the grpc server, errors can be checked through the Err method
 func (s *Server) Serve() {
 	go func() {
@@ -45,13 +101,22 @@ func (s *Server) Serve() {
 	}()
 }
 
+// Addr will get the address the server is listening on
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
 // Err will return any errors that accured while serving, returns nil when none
 func (s Server) Err() error {
 	return s.serveErr
 }
 
-// Close will close the grpc server, returns an error if context is done
+// Close will close the grpc server, returns an error if context is done. The server is
+// reported as not serving while it drains
 func (s *Server) Close(ctx context.Context) error {
+	close(s.quit)
+	s.health.Shutdown()
+
 	done := make(chan struct{})
 	defer close(done)
 	go func() {
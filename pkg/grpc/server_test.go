@@ -0,0 +1,91 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authgrpc "github.com/joshturge-io/auth/pkg/grpc"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+)
+
+func TestServerHealth(t *testing.T) {
+	srv, err := authgrpc.NewServer("127.0.0.1:0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var failing int32
+	srv.WatchHealth(func() error {
+		if atomic.LoadInt32(&failing) == 1 {
+			return errors.New("unable to ping redis")
+		}
+		return nil
+	}, 10*time.Millisecond, log.New(ioutil.Discard, "", 0))
+	srv.Serve()
+	defer srv.Close(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, srv.Addr().String(), grpc.WithInsecure(),
+		grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	// waitFor will wait until the server reports a status
+	waitFor := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		var got healthpb.HealthCheckResponse_ServingStatus
+		for i := 0; i < 50; i++ {
+			resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got = resp.GetStatus(); got == want {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected status: %s got: %s", want, got)
+	}
+
+	waitFor(healthpb.HealthCheckResponse_SERVING)
+	atomic.StoreInt32(&failing, 1)
+	waitFor(healthpb.HealthCheckResponse_NOT_SERVING)
+	atomic.StoreInt32(&failing, 0)
+	waitFor(healthpb.HealthCheckResponse_SERVING)
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		found = found || service.GetName() == "grpc.health.v1.Health"
+	}
+	if !found {
+		t.Errorf("expected health service to be listed got: %v", resp)
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+
+	"github.com/joshturge-io/auth/pkg/auth"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// AdminSecretKey is the metadata key admin calls need to provide the admin secret in
+const AdminSecretKey = "admin-secret"
+
+type GRPCAdminService struct {
+	srv    *auth.Service
+	secret string
+	lg     *log.Logger
+}
+
+func NewGRPCAdminService(as *auth.Service, secret string, lg *log.Logger) *GRPCAdminService {
+	return &GRPCAdminService{as, secret, lg}
+}
+
+// authorise will make sure the caller has provided the admin secret
+func (ga *GRPCAdminService) authorise(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return reasonError(codes.Unauthenticated, proto.ErrorReason_ADMIN_SECRET_REQUIRED,
+			"admin secret not provided")
+	}
+
+	secrets := md.Get(AdminSecretKey)
+	if len(secrets) == 0 {
+		return reasonError(codes.Unauthenticated, proto.ErrorReason_ADMIN_SECRET_REQUIRED,
+			"admin secret not provided")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(secrets[0]), []byte(ga.secret)) != 1 {
+		return reasonError(codes.PermissionDenied, proto.ErrorReason_INVALID_ADMIN_SECRET,
+			"admin secret is not valid")
+	}
+
+	return nil
+}
+
+func (ga *GRPCAdminService) ResetPassword(ctx context.Context,
+	reset *proto.PasswordReset) (*proto.PasswordStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.ResetPassword(ctx, reset.GetUsername(),
+		reset.GetNewPassword()); err != nil {
+		return nil, statusError(ga.lg, "failed to reset password", err)
+	}
+
+	ga.lg.Printf("Password reset for user: %s\n", reset.GetUsername())
+
+	return &proto.PasswordStatus{
+		UserId:  reset.GetUsername(),
+		Success: true,
+		Msg:     "password has been reset",
+	}, nil
+}
+
+func (ga *GRPCAdminService) ListUserSessions(ctx context.Context,
+	req *proto.UserRequest) (*proto.SessionList, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	sessions, err := ga.srv.ListSessions(ctx, req.GetUsername())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to list sessions", err)
+	}
+
+	return sessionsToProto(sessions), nil
+}
+
+func (ga *GRPCAdminService) RevokeUserSession(ctx context.Context,
+	rev *proto.UserSessionRevocation) (*proto.RevokeStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.RevokeSession(ctx, rev.GetUsername(), rev.GetSessionId()); err != nil {
+		return nil, statusError(ga.lg, "failed to revoke session", err)
+	}
+
+	ga.lg.Printf("Session: %s revoked for user: %s\n", rev.GetSessionId(), rev.GetUsername())
+
+	return &proto.RevokeStatus{
+		UserId:  rev.GetUsername(),
+		Success: true,
+		Msg:     "session has been revoked",
+	}, nil
+}
+
+func (ga *GRPCAdminService) RevokeUserSessions(ctx context.Context,
+	req *proto.UserRequest) (*proto.RevokeStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.RevokeAllSessions(ctx, req.GetUsername()); err != nil {
+		return nil, statusError(ga.lg, "failed to revoke sessions", err)
+	}
+
+	ga.lg.Printf("All sessions revoked for user: %s\n", req.GetUsername())
+
+	return &proto.RevokeStatus{
+		UserId:  req.GetUsername(),
+		Success: true,
+		Msg:     "all sessions have been revoked",
+	}, nil
+}
+
+func (ga *GRPCAdminService) AssignRole(ctx context.Context,
+	assign *proto.RoleAssignment) (*proto.RoleStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.AssignRole(ctx, assign.GetUsername(), assign.GetRole()); err != nil {
+		return nil, statusError(ga.lg, "failed to assign role", err)
+	}
+
+	ga.lg.Printf("Role: %s assigned to user: %s\n", assign.GetRole(), assign.GetUsername())
+
+	return &proto.RoleStatus{
+		UserId:  assign.GetUsername(),
+		Success: true,
+		Msg:     "role has been assigned",
+	}, nil
+}
+
+func (ga *GRPCAdminService) RemoveRole(ctx context.Context,
+	assign *proto.RoleAssignment) (*proto.RoleStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.RemoveRole(ctx, assign.GetUsername(), assign.GetRole()); err != nil {
+		return nil, statusError(ga.lg, "failed to remove role", err)
+	}
+
+	ga.lg.Printf("Role: %s removed from user: %s\n", assign.GetRole(), assign.GetUsername())
+
+	return &proto.RoleStatus{
+		UserId:  assign.GetUsername(),
+		Success: true,
+		Msg:     "role has been removed",
+	}, nil
+}
+
+func (ga *GRPCAdminService) ListUserRoles(ctx context.Context,
+	req *proto.UserRequest) (*proto.UserRoles, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	grants, err := ga.srv.Grants(ctx, req.GetUsername())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to list roles", err)
+	}
+
+	return &proto.UserRoles{
+		UserId:      req.GetUsername(),
+		Roles:       grants.Roles,
+		Permissions: grants.Permissions,
+		PermLevel:   int32(grants.PermLevel),
+	}, nil
+}
+
+// lockoutToProto will convert a lockout status to its protobuf message
+func lockoutToProto(status *auth.LockoutStatus) *proto.LockoutStatus {
+	lockout := &proto.LockoutStatus{
+		UserId:       status.UserId,
+		ClientIp:     status.ClientIP,
+		Locked:       !status.LockedUntil.IsZero(),
+		UserFailures: int32(status.UserFailures),
+		IpFailures:   int32(status.IPFailures),
+	}
+
+	if lockout.Locked {
+		lockout.LockedUntil = status.LockedUntil.Unix()
+	}
+
+	if !status.RetryAt.IsZero() {
+		lockout.RetryAt = status.RetryAt.Unix()
+	}
+
+	return lockout
+}
+
+func (ga *GRPCAdminService) GetLockout(ctx context.Context,
+	req *proto.LockoutRequest) (*proto.LockoutStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	status, err := ga.srv.Lockout(ctx, req.GetUsername(), req.GetClientIp())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to get lockout", err)
+	}
+
+	return lockoutToProto(status), nil
+}
+
+func (ga *GRPCAdminService) ClearLockout(ctx context.Context,
+	req *proto.LockoutRequest) (*proto.LockoutStatus, error) {
+
+	if err := ga.authorise(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := ga.srv.ClearLockout(ctx, req.GetUsername(), req.GetClientIp()); err != nil {
+		return nil, statusError(ga.lg, "failed to clear lockout", err)
+	}
+
+	ga.lg.Printf("Lockout cleared for user: %s\n", req.GetUsername())
+
+	return &proto.LockoutStatus{UserId: req.GetUsername(), ClientIp: req.GetClientIp()}, nil
+}
+
+func (ga *GRPCAdminService) RegisterServer(s *grpc.Server) {
+	proto.RegisterAdminServer(s, ga)
+}
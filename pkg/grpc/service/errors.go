@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"time"
+
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/joshturge-io/auth/pkg/auth"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorMapping maps an error from the auth service to a status code and a stable reason
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason proto.ErrorReason
+	// desc is sent to the caller in place of the error, the error itself is only sent when
+	// desc is empty since its text is known not to hold sensitive data
+	desc string
+}
+
+// errorMappings are checked in order, the first one an error matches is used
+var errorMappings = []errorMapping{
+	{auth.ErrInvalidChallenge, codes.Unauthenticated, proto.ErrorReason_INVALID_CREDENTIALS,
+		"invalid username or password"},
+	{auth.ErrUserNotExist, codes.NotFound, proto.ErrorReason_USER_NOT_FOUND,
+		"user does not exist"},
+	{auth.ErrUserExist, codes.AlreadyExists, proto.ErrorReason_USER_EXISTS,
+		"user already exists"},
+	{auth.ErrInvalidUserId, codes.InvalidArgument, proto.ErrorReason_INVALID_USER_ID,
+		"user id is not valid"},
+	{auth.ErrWeakPassword, codes.InvalidArgument, proto.ErrorReason_WEAK_PASSWORD, ""},
+	{auth.ErrRefreshReused, codes.Unauthenticated, proto.ErrorReason_REFRESH_TOKEN_REUSED,
+		"refresh token has been reused, the session has been revoked"},
+	{auth.ErrInvalidSession, codes.Unauthenticated, proto.ErrorReason_INVALID_SESSION,
+		"session is not valid"},
+	{auth.ErrSessionNotExist, codes.NotFound, proto.ErrorReason_SESSION_NOT_FOUND,
+		"session does not exist"},
+	{token.ErrJWExpired, codes.Unauthenticated, proto.ErrorReason_TOKEN_EXPIRED,
+		"token has expired"},
+	{token.ErrJWInvalid, codes.Unauthenticated, proto.ErrorReason_INVALID_TOKEN,
+		"token is invalid"},
+	{auth.ErrAccountLocked, codes.ResourceExhausted, proto.ErrorReason_ACCOUNT_LOCKED,
+		"account is locked"},
+	{auth.ErrTooManyAttempts, codes.ResourceExhausted, proto.ErrorReason_TOO_MANY_ATTEMPTS,
+		"too many failed attempts"},
+	{auth.ErrMFARequired, codes.FailedPrecondition, proto.ErrorReason_MFA_REQUIRED,
+		"multi-factor authentication is required"},
+	{auth.ErrInvalidMFACode, codes.PermissionDenied, proto.ErrorReason_INVALID_MFA_CODE,
+		"mfa code is not valid"},
+	{auth.ErrInvalidMFAChallenge, codes.Unauthenticated,
+		proto.ErrorReason_INVALID_MFA_CHALLENGE, "mfa challenge is not valid"},
+	{auth.ErrMFAEnabled, codes.FailedPrecondition, proto.ErrorReason_MFA_ENABLED,
+		"multi-factor authentication is already enabled"},
+	{auth.ErrMFANotEnabled, codes.FailedPrecondition, proto.ErrorReason_MFA_NOT_ENABLED,
+		"multi-factor authentication is not enabled"},
+	{auth.ErrMFANotEnrolled, codes.FailedPrecondition, proto.ErrorReason_MFA_NOT_ENROLLED,
+		"totp enrollment has not been started"},
+	{auth.ErrRoleNotExist, codes.InvalidArgument, proto.ErrorReason_ROLE_NOT_FOUND, ""},
+	{context.DeadlineExceeded, codes.DeadlineExceeded, proto.ErrorReason_TIMEOUT,
+		"request timed out"},
+	{context.Canceled, codes.Canceled, proto.ErrorReason_CANCELLED, "request was cancelled"},
+}
+
+// credentialsError will hide whether a user exists from calls that check a challenge, an
+// unknown user fails the same way as an incorrect password
+func credentialsError(err error) error {
+	if errors.Is(err, auth.ErrUserNotExist) {
+		return auth.ErrInvalidChallenge
+	}
+
+	return err
+}
+
+// isUnavailable will check if an error was caused by the repository being unreachable
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// withDetails will attach an ErrorInfo holding the reason along with any other details to a
+// status
+func withDetails(st *status.Status, reason proto.ErrorReason,
+	details []protobuf.Message) error {
+	detailed, err := st.WithDetails(append([]protobuf.Message{&proto.ErrorInfo{Reason: reason}},
+		details...)...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// reasonError will create a status error with an ErrorInfo detail holding the reason
+func reasonError(code codes.Code, reason proto.ErrorReason, msg string) error {
+	return withDetails(status.New(code, msg), reason, nil)
+}
+
+// statusError will map an error from the auth service to a status error. The status message
+// never holds the text of an unexpected error since it can hold tokens or other sensitive
+// data, those errors are logged instead
+func statusError(lg *log.Logger, msg string, err error) error {
+	for _, mapping := range errorMappings {
+		if !errors.Is(err, mapping.err) {
+			continue
+		}
+
+		desc := mapping.desc
+		if desc == "" {
+			desc = err.Error()
+		}
+
+		var details []protobuf.Message
+		switch mapping.code {
+		case codes.InvalidArgument:
+			details = append(details, &errdetails.BadRequest{
+				FieldViolations: []*errdetails.BadRequest_FieldViolation{{Description: desc}},
+			})
+		case codes.FailedPrecondition:
+			details = append(details, &errdetails.PreconditionFailure{
+				Violations: []*errdetails.PreconditionFailure_Violation{{
+					Type:        "MFA",
+					Description: desc,
+				}},
+			})
+		}
+
+		var retry *auth.RetryError
+		if errors.As(err, &retry) {
+			details = append(details, &errdetails.RetryInfo{
+				RetryDelay: ptypes.DurationProto(time.Until(retry.RetryAt)),
+			})
+		}
+
+		return withDetails(status.New(mapping.code, msg+": "+desc), mapping.reason, details)
+	}
+
+	lg.Printf("ERROR: %s: %s\n", msg, err.Error())
+
+	if isUnavailable(err) {
+		return withDetails(status.New(codes.Unavailable, msg+": service unavailable"),
+			proto.ErrorReason_REPOSITORY_UNAVAILABLE, nil)
+	}
+
+	return withDetails(status.New(codes.Internal, msg+": internal error"),
+		proto.ErrorReason_INTERNAL, nil)
+}
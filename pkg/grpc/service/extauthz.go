@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	core "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authz "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	envoytype "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/joshturge-io/auth/pkg/auth"
+	authhttp "github.com/joshturge-io/auth/pkg/http"
+	"github.com/joshturge-io/auth/pkg/token"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// GRPCExtAuthzService is the envoy external authorization service, requests with a valid jwt
+// are allowed with the identity of the user set in headers
+type GRPCExtAuthzService struct {
+	srv        *auth.Service
+	cookieName string
+	lg         *log.Logger
+}
+
+// NewGRPCExtAuthzService will create a new ext_authz service, the jwt of a request is read
+// from the cookie when it doesn't have an Authorization header and cookieName isn't empty
+func NewGRPCExtAuthzService(as *auth.Service, cookieName string,
+	lg *log.Logger) *GRPCExtAuthzService {
+	return &GRPCExtAuthzService{as, cookieName, lg}
+}
+
+// header will create a header that replaces any header of the request with the same key
+func header(key, value string) *core.HeaderValueOption {
+	return &core.HeaderValueOption{
+		Header: &core.HeaderValue{Key: key, Value: value},
+		Append: &wrappers.BoolValue{Value: false},
+	}
+}
+
+// deniedResponse will create a response denying a request with a 401
+func deniedResponse(msg string) *authz.CheckResponse {
+	return &authz.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.Unauthenticated), Message: msg},
+		HttpResponse: &authz.CheckResponse_DeniedResponse{
+			DeniedResponse: &authz.DeniedHttpResponse{
+				Status: &envoytype.HttpStatus{Code: envoytype.StatusCode_Unauthorized},
+				Headers: []*core.HeaderValueOption{
+					header("www-authenticate", `Bearer realm="auth"`),
+				},
+				Body: msg,
+			},
+		},
+	}
+}
+
+func (ga *GRPCExtAuthzService) Check(ctx context.Context,
+	req *authz.CheckRequest) (*authz.CheckResponse, error) {
+
+	r := &http.Request{Header: http.Header{}}
+	for key, value := range req.GetAttributes().GetRequest().GetHttp().GetHeaders() {
+		r.Header.Set(key, value)
+	}
+
+	tokenStr := authhttp.BearerToken(r, ga.cookieName)
+	if tokenStr == "" {
+		return deniedResponse("token not provided"), nil
+	}
+
+	identity, err := ga.srv.Identify(ctx, tokenStr)
+	if err != nil {
+		if errors.Is(err, token.ErrJWExpired) {
+			return deniedResponse("token has expired"), nil
+		}
+		if errors.Is(err, token.ErrJWInvalid) {
+			return deniedResponse("token is invalid"), nil
+		}
+		// envoy decides if the request is allowed when the check fails
+		return nil, statusError(ga.lg, "failed to check request", err)
+	}
+
+	// identity headers are always set so they can't be spoofed by the request
+	return &authz.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authz.CheckResponse_OkResponse{
+			OkResponse: &authz.OkHttpResponse{
+				Headers: []*core.HeaderValueOption{
+					header(strings.ToLower(authhttp.UserIdHeader), identity.UserId),
+					header(strings.ToLower(authhttp.UserRolesHeader),
+						strings.Join(identity.Roles, ",")),
+					header(strings.ToLower(authhttp.UserPermsHeader),
+						strings.Join(identity.Permissions, ",")),
+					header(strings.ToLower(authhttp.UserLevelHeader),
+						strconv.Itoa(identity.PermLevel)),
+				},
+			},
+		},
+	}, nil
+}
+
+func (ga *GRPCExtAuthzService) RegisterServer(s *grpc.Server) {
+	authz.RegisterAuthorizationServer(s, ga)
+}
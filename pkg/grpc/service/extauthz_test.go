@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	authz "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	envoytype "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/grpc/service"
+	"google.golang.org/grpc/codes"
+)
+
+// checkRequest will create a check request of a http request with headers
+func checkRequest(headers map[string]string) *authz.CheckRequest {
+	return &authz.CheckRequest{Attributes: &authz.AttributeContext{
+		Request: &authz.AttributeContext_Request{
+			Http: &authz.AttributeContext_HttpRequest{Headers: headers},
+		},
+	}}
+}
+
+func TestExtAuthzCheck(t *testing.T) {
+	ctx := context.Background()
+	extAuthz := service.NewGRPCExtAuthzService(authSvc, "access_token",
+		log.New(ioutil.Discard, "", 0))
+
+	session, err := srv.Login(ctx, &proto.Credentials{Username: "user",
+		Password: "123password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, headers := range []map[string]string{
+		{"authorization": "Bearer " + session.GetJwt(), "x-user-id": "admin"},
+		{"cookie": "access_token=" + session.GetJwt()},
+	} {
+		resp, err := extAuthz.Check(ctx, checkRequest(headers))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if codes.Code(resp.GetStatus().GetCode()) != codes.OK {
+			t.Fatalf("expected request to be allowed got: %v", resp.GetStatus())
+		}
+
+		found := false
+		for _, option := range resp.GetOkResponse().GetHeaders() {
+			if option.GetHeader().GetKey() == "x-user-id" {
+				found = option.GetHeader().GetValue() == "user" && !option.GetAppend().GetValue()
+			}
+		}
+		if !found {
+			t.Errorf("expected x-user-id to be replaced got: %v", resp.GetOkResponse())
+		}
+	}
+
+	for _, headers := range []map[string]string{
+		{"authorization": "Bearer not.a.token"},
+		{"authorization": "Basic " + session.GetJwt()},
+		{},
+	} {
+		resp, err := extAuthz.Check(ctx, checkRequest(headers))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if codes.Code(resp.GetStatus().GetCode()) != codes.Unauthenticated ||
+			resp.GetDeniedResponse().GetStatus().GetCode() != envoytype.StatusCode_Unauthorized {
+			t.Errorf("expected request to be denied got: %v", resp)
+		}
+	}
+}
@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"log"
+	"net"
 
 	"github.com/joshturge-io/auth/pkg/auth"
 	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 )
 
 type GRPCAuthService struct {
@@ -19,38 +21,125 @@ func NewGRPCAuthService(as *auth.Service, lg *log.Logger) *GRPCAuthService {
 	return &GRPCAuthService{as, lg}
 }
 
-func (ga *GRPCAuthService) Login(ctx context.Context, cred *proto.Credentials) (*proto.Session,
-	error) {
+// deviceFromContext will get the user agent and address of the client making the call
+func deviceFromContext(ctx context.Context) auth.Device {
+	dev := auth.Device{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if agents := md.Get("user-agent"); len(agents) > 0 {
+			dev.UserAgent = agents[0]
+		}
+	}
 
-	session, err := ga.srv.SessionWithChallenge(ctx, cred.GetUsername(), cred.GetPassword())
-	if err != nil {
-		return nil, grpc.Errorf(codes.PermissionDenied,
-			"failed to create session from challenge: %s", err.Error())
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		dev.ClientIP = host
+	}
+
+	return dev
+}
+
+// sessionFromProto will create a session from a proto session and the calling client
+func sessionFromProto(ctx context.Context, sess *proto.Session) *auth.Session {
+	return &auth.Session{
+		Id:      sess.GetSessionId(),
+		UserId:  sess.GetUserId(),
+		Refresh: sess.GetRefreshToken(),
+		JWT:     sess.GetJwt(),
+		Device:  deviceFromContext(ctx),
 	}
+}
 
+// sessionsToProto will create a proto session list from sessions without their tokens
+func sessionsToProto(sessions []*auth.Session) *proto.SessionList {
+	list := &proto.SessionList{Sessions: make([]*proto.SessionInfo, len(sessions))}
+	for i, session := range sessions {
+		list.Sessions[i] = &proto.SessionInfo{
+			SessionId:         session.Id,
+			UserId:            session.UserId,
+			CreatedAt:         session.CreatedAt.Unix(),
+			LastUsed:          session.LastUsed.Unix(),
+			RefreshExpiration: session.RefreshExpiration.Unix(),
+			UserAgent:         session.UserAgent,
+			ClientIp:          session.ClientIP,
+		}
+	}
+
+	return list
+}
+
+// sessionToProto will create a proto session from a session
+func sessionToProto(session *auth.Session) *proto.Session {
 	return &proto.Session{
 		UserId:            session.UserId,
 		Jwt:               session.JWT,
 		RefreshToken:      session.Refresh,
 		RefreshExpiration: session.RefreshExpiration.Unix(),
-	}, nil
+		SessionId:         session.Id,
+	}
+}
+
+func (ga *GRPCAuthService) Register(ctx context.Context,
+	reg *proto.Registration) (*proto.RegisterStatus, error) {
+
+	session, err := ga.srv.Register(ctx, reg.GetUsername(), reg.GetPassword(),
+		reg.GetCreateSession(), deviceFromContext(ctx))
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to register user", err)
+	}
+
+	status := &proto.RegisterStatus{UserId: reg.GetUsername()}
+	if session != nil {
+		status.Session = sessionToProto(session)
+	}
+
+	return status, nil
+}
+
+func (ga *GRPCAuthService) Login(ctx context.Context, cred *proto.Credentials) (*proto.Session,
+	error) {
+
+	session, mfaToken, err := ga.srv.Login(ctx, cred.GetUsername(), cred.GetPassword(),
+		deviceFromContext(ctx))
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to create session from challenge",
+			credentialsError(err))
+	}
+
+	if mfaToken != "" {
+		return &proto.Session{
+			UserId:      cred.GetUsername(),
+			MfaRequired: true,
+			MfaToken:    mfaToken,
+		}, nil
+	}
+
+	return sessionToProto(session), nil
+}
+
+func (ga *GRPCAuthService) VerifyMFA(ctx context.Context,
+	verify *proto.MFAVerification) (*proto.Session, error) {
+
+	session, err := ga.srv.VerifyMFA(ctx, verify.GetMfaToken(), verify.GetCode(),
+		deviceFromContext(ctx))
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to verify mfa", err)
+	}
+
+	return sessionToProto(session), nil
 }
 
 func (ga *GRPCAuthService) Refresh(ctx context.Context, sess *proto.Session) (*proto.Session,
 	error) {
 
-	session, err := ga.srv.Renew(ctx, &auth.Session{UserId: sess.GetUserId(),
-		Refresh: sess.GetRefreshToken(), JWT: sess.GetJwt()})
+	session, err := ga.srv.Renew(ctx, sessionFromProto(ctx, sess))
 	if err != nil {
-		return nil, grpc.Errorf(codes.Internal, "failed to renew session: %s", err.Error())
+		return nil, statusError(ga.lg, "failed to renew session", err)
 	}
 
-	return &proto.Session{
-		UserId:            session.UserId,
-		Jwt:               session.JWT,
-		RefreshToken:      session.Refresh,
-		RefreshExpiration: session.RefreshExpiration.Unix(),
-	}, nil
+	return sessionToProto(session), nil
 }
 
 func (ga *GRPCAuthService) ValidateJWT(ctx context.Context,
@@ -58,7 +147,15 @@ func (ga *GRPCAuthService) ValidateJWT(ctx context.Context,
 
 	isValid, err := ga.srv.IsValidJWT(jw.GetToken())
 	if err != nil {
-		return nil, grpc.Errorf(codes.Internal, "failed to validate token: %s", err.Error())
+		return nil, statusError(ga.lg, "failed to validate token", err)
+	}
+
+	if isValid {
+		revoked, err := ga.srv.IsRevokedJWT(ctx, jw.GetToken())
+		if err != nil {
+			return nil, statusError(ga.lg, "failed to validate token", err)
+		}
+		isValid = !revoked
 	}
 
 	return &proto.ValidityStatus{Valid: isValid}, nil
@@ -67,9 +164,8 @@ func (ga *GRPCAuthService) ValidateJWT(ctx context.Context,
 func (ga *GRPCAuthService) Logout(ctx context.Context,
 	sess *proto.Session) (*proto.LogoutStatus, error) {
 
-	if err := ga.srv.DestroySession(ctx, &auth.Session{UserId: sess.GetUserId(),
-		Refresh: sess.GetRefreshToken(), JWT: sess.GetJwt()}); err != nil {
-		return nil, grpc.Errorf(codes.Internal, "failed to destroy session: %s", err.Error())
+	if err := ga.srv.DestroySession(ctx, sessionFromProto(ctx, sess)); err != nil {
+		return nil, statusError(ga.lg, "failed to destroy session", err)
 	}
 
 	return &proto.LogoutStatus{
@@ -79,6 +175,216 @@ func (ga *GRPCAuthService) Logout(ctx context.Context,
 	}, nil
 }
 
-func (ga *GRPCAuthService) Register(s *grpc.Server) {
+func (ga *GRPCAuthService) ChangePassword(ctx context.Context,
+	change *proto.PasswordChange) (*proto.PasswordStatus, error) {
+
+	if err := ga.srv.ChangePassword(ctx, change.GetUsername(), change.GetOldPassword(),
+		change.GetNewPassword(), deviceFromContext(ctx)); err != nil {
+		return nil, statusError(ga.lg, "failed to change password", credentialsError(err))
+	}
+
+	return &proto.PasswordStatus{
+		UserId:  change.GetUsername(),
+		Success: true,
+		Msg:     "password has been changed",
+	}, nil
+}
+
+func (ga *GRPCAuthService) ListSessions(ctx context.Context,
+	jw *proto.JWT) (*proto.SessionList, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, jw.GetToken())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	sessions, err := ga.srv.ListSessions(ctx, userId)
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to list sessions", err)
+	}
+
+	return sessionsToProto(sessions), nil
+}
+
+func (ga *GRPCAuthService) RevokeSession(ctx context.Context,
+	rev *proto.SessionRevocation) (*proto.RevokeStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, rev.GetJwt())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	if err = ga.srv.RevokeSession(ctx, userId, rev.GetSessionId()); err != nil {
+		return nil, statusError(ga.lg, "failed to revoke session", err)
+	}
+
+	return &proto.RevokeStatus{
+		UserId:  userId,
+		Success: true,
+		Msg:     "session has been revoked",
+	}, nil
+}
+
+func (ga *GRPCAuthService) RevokeAllSessions(ctx context.Context,
+	jw *proto.JWT) (*proto.RevokeStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, jw.GetToken())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	if err = ga.srv.RevokeAllSessions(ctx, userId); err != nil {
+		return nil, statusError(ga.lg, "failed to revoke sessions", err)
+	}
+
+	return &proto.RevokeStatus{
+		UserId:  userId,
+		Success: true,
+		Msg:     "all sessions have been revoked",
+	}, nil
+}
+
+func (ga *GRPCAuthService) EnrollTOTP(ctx context.Context,
+	jw *proto.JWT) (*proto.TOTPEnrollment, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, jw.GetToken())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	enrollment, err := ga.srv.EnrollTOTP(ctx, userId)
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to enroll totp", err)
+	}
+
+	return &proto.TOTPEnrollment{Secret: enrollment.Secret, Uri: enrollment.URI}, nil
+}
+
+func (ga *GRPCAuthService) ConfirmTOTP(ctx context.Context,
+	code *proto.TOTPCode) (*proto.MFAStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, code.GetJwt())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	recoveryCodes, err := ga.srv.ConfirmTOTP(ctx, userId, code.GetCode(),
+		deviceFromContext(ctx))
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to confirm totp", err)
+	}
+
+	return &proto.MFAStatus{
+		UserId:                 userId,
+		Success:                true,
+		Msg:                    "totp has been enabled",
+		TotpEnabled:            true,
+		RecoveryCodes:          recoveryCodes,
+		RecoveryCodesRemaining: int32(len(recoveryCodes)),
+	}, nil
+}
+
+func (ga *GRPCAuthService) DisableTOTP(ctx context.Context,
+	code *proto.TOTPCode) (*proto.MFAStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, code.GetJwt())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	if err = ga.srv.DisableTOTP(ctx, userId, code.GetCode(),
+		deviceFromContext(ctx)); err != nil {
+		return nil, statusError(ga.lg, "failed to disable totp", err)
+	}
+
+	return &proto.MFAStatus{
+		UserId:  userId,
+		Success: true,
+		Msg:     "totp has been disabled",
+	}, nil
+}
+
+func (ga *GRPCAuthService) RegenerateRecoveryCodes(ctx context.Context,
+	code *proto.TOTPCode) (*proto.MFAStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, code.GetJwt())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	recoveryCodes, err := ga.srv.RegenerateRecoveryCodes(ctx, userId, code.GetCode(),
+		deviceFromContext(ctx))
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to regenerate recovery codes", err)
+	}
+
+	return &proto.MFAStatus{
+		UserId:                 userId,
+		Success:                true,
+		Msg:                    "recovery codes have been regenerated",
+		TotpEnabled:            true,
+		RecoveryCodes:          recoveryCodes,
+		RecoveryCodesRemaining: int32(len(recoveryCodes)),
+	}, nil
+}
+
+func (ga *GRPCAuthService) GetMFAStatus(ctx context.Context,
+	jw *proto.JWT) (*proto.MFAStatus, error) {
+
+	userId, err := ga.srv.Authenticate(ctx, jw.GetToken())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authenticate", err)
+	}
+
+	status, err := ga.srv.MFAStatus(ctx, userId)
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to get mfa status", err)
+	}
+
+	return &proto.MFAStatus{
+		UserId:                 userId,
+		Success:                true,
+		TotpEnabled:            status.TOTPEnabled,
+		RecoveryCodesRemaining: int32(status.RecoveryCodes),
+	}, nil
+}
+
+func (ga *GRPCAuthService) Authorize(ctx context.Context,
+	req *proto.AuthorizeRequest) (*proto.AuthorizeResponse, error) {
+
+	decision, err := ga.srv.Authorize(ctx, req.GetJwt(), req.GetAction(), req.GetResource())
+	if err != nil {
+		return nil, statusError(ga.lg, "failed to authorize", err)
+	}
+
+	return &proto.AuthorizeResponse{
+		Allowed: decision.Allowed,
+		Reason:  decision.Reason,
+	}, nil
+}
+
+func (ga *GRPCAuthService) GetKeys(ctx context.Context,
+	req *proto.KeySetRequest) (*proto.KeySet, error) {
+
+	jwks := ga.srv.KeySet()
+	keySet := &proto.KeySet{Keys: make([]*proto.JWK, len(jwks.Keys))}
+	for i, key := range jwks.Keys {
+		keySet.Keys[i] = &proto.JWK{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+			Y:   key.Y,
+		}
+	}
+
+	return keySet, nil
+}
+
+func (ga *GRPCAuthService) RegisterServer(s *grpc.Server) {
 	proto.RegisterAuthenticationServer(s, ga)
 }
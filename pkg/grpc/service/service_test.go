@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/auth"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/grpc/service"
+	"github.com/joshturge-io/auth/pkg/repository"
+	"github.com/joshturge-io/auth/pkg/token"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	authSvc *auth.Service
+	srv     *service.GRPCAuthService
+)
+
+func init() {
+	keyring, err := token.NewKeyring(token.NewHMACKey("secret"))
+	if err != nil {
+		panic(err)
+	}
+
+	repository.TestUser = map[string]string{
+		"salt": "25b072f201ef24e750dcc558eaf2d8f3",
+		"hash": "1743545c93d519060a72e5671a66cbe898163b41d8be2a92a57ac3b6a2650c8394cf4f009aa0df642721145694879ace89c1a9973ff601538220d6a59f665524022fc789a3f6512d7f4654ff8f39c7ba7ec5b12e93c08df97be9f8a4",
+	}
+	repository.TestSessions = map[string]*repository.Session{}
+	repository.TestUsers = map[string]map[string]string{}
+	repository.TestRotated = map[string][]string{}
+	repository.TestBlacklist = []string{}
+	repository.TestRoles = map[string][]string{}
+	repository.TestMFA = map[string]*repository.MFA{}
+	repository.TestMFAChallenges = map[string]*repository.MFAChallenge{}
+	repository.TestRecoveryCodes = map[string][]string{}
+	repository.TestFailures = map[string][]time.Time{}
+	repository.TestLockouts = map[string]time.Time{}
+
+	authSvc = auth.NewService(keyring, repository.NewTestRepository(),
+		[]string{"vcMGBMVbxobHRRdX1WBYq0T4L3UYWQLd"}, &auth.Options{
+			RefreshTokenLength:     32,
+			JWTokenExpiration:      15 * time.Minute,
+			RefreshTokenExpiration: 24 * time.Hour,
+			RefreshSecret:          "refresh_secret",
+			SaltLength:             16,
+		})
+	srv = service.NewGRPCAuthService(authSvc, log.New(ioutil.Discard, "", 0))
+}
+
+// expectStatus will check the code and reason of a status error
+func expectStatus(t *testing.T, err error, code codes.Code, reason proto.ErrorReason) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("expected code: %s got: %v", code, err)
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*proto.ErrorInfo); ok {
+			if info.GetReason() != reason {
+				t.Errorf("expected reason: %s got: %s", reason, info.GetReason())
+			}
+			return
+		}
+	}
+
+	t.Errorf("expected ErrorInfo in the status details got: %v", st.Details())
+}
+
+func TestStatusErrors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := srv.Login(ctx, &proto.Credentials{Username: "user", Password: "wrong_password"})
+	expectStatus(t, err, codes.Unauthenticated, proto.ErrorReason_INVALID_CREDENTIALS)
+
+	// an unknown user fails the same way as an incorrect password
+	_, err = srv.Login(ctx, &proto.Credentials{Username: "nobody", Password: "wrong_password"})
+	expectStatus(t, err, codes.Unauthenticated, proto.ErrorReason_INVALID_CREDENTIALS)
+
+	_, err = srv.VerifyMFA(ctx, &proto.MFAVerification{MfaToken: "unknown", Code: "123456"})
+	expectStatus(t, err, codes.Unauthenticated, proto.ErrorReason_INVALID_MFA_CHALLENGE)
+
+	// the token must never be sent back in the status message
+	secretToken := "eyJhbGciOiJIUzI1NiJ9.c2VjcmV0X2NsYWltcw.c2lnbmF0dXJl"
+	_, err = srv.ListSessions(ctx, &proto.JWT{Token: secretToken})
+	expectStatus(t, err, codes.Unauthenticated, proto.ErrorReason_INVALID_TOKEN)
+	if strings.Contains(status.Convert(err).Message(), secretToken) {
+		t.Errorf("status message leaked the token: %s", status.Convert(err).Message())
+	}
+}
@@ -0,0 +1,216 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// ErrNoClientCAs is returned when a CA bundle doesn't hold any certificates
+var ErrNoClientCAs = errors.New("no certificates found in client ca bundle")
+
+// CertReloader serves a certificate and client CA bundle loaded from files. The files are
+// checked for changes at most once every interval when a client connects, and reloaded when
+// they have changed
+type CertReloader struct {
+	certFile, keyFile, caFile string
+	interval                  time.Duration
+	lg                        *log.Logger
+
+	mu        sync.Mutex
+	config    *tls.Config
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+// NewCertReloader will load a certificate and key, and a CA bundle client certificates are
+// verified against. Clients aren't asked for a certificate when caFile is empty
+func NewCertReloader(certFile, keyFile, caFile string, interval time.Duration,
+	lg *log.Logger) (*CertReloader, error) {
+	cr := &CertReloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		interval: interval,
+		lg:       lg,
+	}
+
+	modTime, err := cr.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cr.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return cr, nil
+}
+
+// files will get the paths of every file the reloader loads
+func (cr *CertReloader) files() []string {
+	files := []string{cr.certFile, cr.keyFile}
+	if cr.caFile != "" {
+		files = append(files, cr.caFile)
+	}
+
+	return files
+}
+
+// latestModTime will get the modification time of the most recently changed file
+func (cr *CertReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, file := range cr.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat tls file: %w", err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// load will read the files and replace the served config
+func (cr *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(cr.certFile, cr.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load tls certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+		NextProtos:   []string{"h2"},
+	}
+
+	if cr.caFile != "" {
+		bundle, err := ioutil.ReadFile(cr.caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client ca bundle: %w", err)
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(bundle) {
+			return fmt.Errorf("%s: %w", cr.caFile, ErrNoClientCAs)
+		}
+
+		// certificates are only required by some methods, which is checked by
+		// ClientCertInterceptor
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	cr.config = config
+	cr.modTime = modTime
+
+	return nil
+}
+
+// reload will load the files again when they have changed since they were last loaded. The
+// previous config keeps being served when the files can't be loaded
+func (cr *CertReloader) reload() {
+	if time.Since(cr.checkedAt) < cr.interval {
+		return
+	}
+	cr.checkedAt = time.Now()
+
+	modTime, err := cr.latestModTime()
+	if err != nil {
+		cr.lg.Printf("ERROR: failed to reload tls certificate: %s\n", err.Error())
+		return
+	}
+
+	if !modTime.After(cr.modTime) {
+		return
+	}
+
+	if err = cr.load(modTime); err != nil {
+		cr.lg.Printf("ERROR: failed to reload tls certificate: %s\n", err.Error())
+		return
+	}
+
+	cr.lg.Println("Reloaded tls certificate")
+}
+
+// GetConfigForClient will get the config a client connection is served with
+func (cr *CertReloader) GetConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	cr.reload()
+
+	return cr.config, nil
+}
+
+// Credentials will create transport credentials serving the reloaded config
+func (cr *CertReloader) Credentials() credentials.TransportCredentials {
+	return credentials.NewTLS(&tls.Config{GetConfigForClient: cr.GetConfigForClient})
+}
+
+// matchMethod will check if a full method is matched by a service (proto.auth.Admin), a
+// method name (ValidateJWT) or a full method (/proto.auth.Admin/ResetPassword), names are
+// matched case insensitively
+func matchMethod(methods []string, fullMethod string) bool {
+	i := strings.LastIndex(fullMethod, "/")
+	service, name := strings.TrimPrefix(fullMethod[:i], "/"), fullMethod[i+1:]
+	for _, method := range methods {
+		for _, key := range []string{fullMethod, service, name} {
+			if strings.EqualFold(method, key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasClientCert will check if the peer making a call presented a verified client certificate
+func hasClientCert(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	return ok && len(info.State.VerifiedChains) > 0
+}
+
+// ClientCertInterceptor will only let calls to methods through when the peer presented a
+// verified client certificate, other methods don't require one
+func ClientCertInterceptor(methods []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if !matchMethod(methods, info.FullMethod) || hasClientCert(ctx) {
+			return handler(ctx, req)
+		}
+
+		st := status.Newf(codes.Unauthenticated, "client certificate required for: %s",
+			info.FullMethod)
+		if detailed, err := st.WithDetails(
+			&proto.ErrorInfo{Reason: proto.ErrorReason_CLIENT_CERT_REQUIRED},
+		); err == nil {
+			st = detailed
+		}
+
+		return nil, st.Err()
+	}
+}
@@ -0,0 +1,206 @@
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	authgrpc "github.com/joshturge-io/auth/pkg/grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// newCert will create a certificate signed by parent, the certificate is self signed when
+// parent is nil
+func newCert(t *testing.T, name string, parent *tls.Certificate) *tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		DNSNames:              []string{name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+	}
+
+	signer, signerKey := template, interface{}(key)
+	if parent != nil {
+		signer, signerKey = parent.Leaf, parent.PrivateKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writeCert will write a certificate and its key as PEM files
+func writeCert(t *testing.T, cert *tls.Certificate, certFile, keyFile string) {
+	t.Helper()
+	keyDer, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE",
+		Bytes: cert.Certificate[0]}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY",
+		Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	caFile := filepath.Join(dir, "ca.crt")
+
+	ca := newCert(t, "ca", nil)
+	writeCert(t, ca, caFile, filepath.Join(dir, "ca.key"))
+	writeCert(t, newCert(t, "localhost", ca), certFile, keyFile)
+
+	reloader, err := authgrpc.NewCertReloader(certFile, keyFile, caFile, 0,
+		log.New(ioutil.Discard, "", 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0",
+		&tls.Config{GetConfigForClient: reloader.GetConfigForClient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	verified := make(chan int, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			tlsConn := conn.(*tls.Conn)
+			tlsConn.Handshake()
+			verified <- len(tlsConn.ConnectionState().VerifiedChains)
+			conn.Close()
+		}
+	}()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Leaf)
+	client := newCert(t, "client", ca)
+
+	// handshake will connect to the listener, returning the serial of the server
+	// certificate and the number of verified client chains
+	handshake := func(certs ...tls.Certificate) (*big.Int, int) {
+		t.Helper()
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{
+			RootCAs:      roots,
+			ServerName:   "localhost",
+			Certificates: certs,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber, <-verified
+	}
+
+	first, chains := handshake()
+	if chains != 0 {
+		t.Errorf("expected no verified client chains got: %d", chains)
+	}
+
+	if _, chains = handshake(*client); chains != 1 {
+		t.Errorf("expected a verified client chain got: %d", chains)
+	}
+
+	// replace the certificate and make sure the change is seen
+	writeCert(t, newCert(t, "localhost", ca), certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	if err = os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if second, _ := handshake(); second.Cmp(first) == 0 {
+		t.Error("expected the reloaded certificate to be served")
+	}
+}
+
+func TestClientCertInterceptor(t *testing.T) {
+	interceptor := authgrpc.ClientCertInterceptor([]string{"proto.auth.Admin", "validatejwt"})
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	withoutCert := peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000},
+		AuthInfo: credentials.TLSInfo{},
+	})
+	withCert := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 50000},
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{{}}},
+		}},
+	})
+
+	for _, method := range []string{"/proto.auth.Admin/ResetPassword",
+		"/proto.auth.Authentication/ValidateJWT"} {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		if _, err := interceptor(withoutCert, nil, info, handler); status.Code(err) !=
+			codes.Unauthenticated {
+			t.Errorf("expected Unauthenticated calling %s without a certificate got: %v",
+				method, err)
+		}
+
+		if _, err := interceptor(withCert, nil, info, handler); err != nil {
+			t.Errorf("expected call to %s with a certificate to be allowed got: %v",
+				method, err)
+		}
+	}
+
+	login := &grpc.UnaryServerInfo{FullMethod: "/proto.auth.Authentication/Login"}
+	if _, err := interceptor(withoutCert, nil, login, handler); err != nil {
+		t.Errorf("expected call without a certificate to be allowed got: %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/joshturge-io/auth/pkg/auth"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// ForwardAuthPath is where reverse proxies send requests to be authenticated
+const ForwardAuthPath = "/forward-auth"
+
+// Headers set on authenticated forward auth responses, proxies copy them onto the request
+// sent to the protected app
+const (
+	UserIdHeader    = "X-User-Id"
+	UserRolesHeader = "X-User-Roles"
+	UserPermsHeader = "X-User-Permissions"
+	UserLevelHeader = "X-User-Perm-Level"
+)
+
+// bearerPrefix is the scheme of Authorization headers holding a jwt
+const bearerPrefix = "Bearer "
+
+// Identifier gets the identity a jwt was issued to
+type Identifier interface {
+	Identify(ctx context.Context, tokenStr string) (*auth.Identity, error)
+}
+
+// BearerToken will get the jwt of a request from the Authorization header, or from the
+// cookie when the header isn't set and cookieName isn't empty
+func BearerToken(r *http.Request, cookieName string) string {
+	if header := r.Header.Get("Authorization"); header != "" {
+		if len(header) > len(bearerPrefix) &&
+			strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(header[len(bearerPrefix):])
+		}
+		return ""
+	}
+
+	if cookieName != "" {
+		if cookie, err := r.Cookie(cookieName); err == nil {
+			return cookie.Value
+		}
+	}
+
+	return ""
+}
+
+// ForwardAuthHandler will authenticate requests for reverse proxies like nginx auth_request
+// and Traefik ForwardAuth. Requests with a valid jwt that hasn't been revoked get a 200 with
+// the identity of the user in headers, other requests get a 401
+func ForwardAuthHandler(id Identifier, cookieName string, lg *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+
+		tokenStr := BearerToken(r, cookieName)
+		if tokenStr == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="auth"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		identity, err := id.Identify(r.Context(), tokenStr)
+		if err != nil {
+			if errors.Is(err, token.ErrJWInvalid) || errors.Is(err, token.ErrJWExpired) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="auth", error="invalid_token"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized),
+					http.StatusUnauthorized)
+				return
+			}
+
+			lg.Printf("ERROR: failed to authenticate forwarded request: %s\n", err.Error())
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable),
+				http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set(UserIdHeader, identity.UserId)
+		w.Header().Set(UserRolesHeader, strings.Join(identity.Roles, ","))
+		w.Header().Set(UserPermsHeader, strings.Join(identity.Permissions, ","))
+		w.Header().Set(UserLevelHeader, strconv.Itoa(identity.PermLevel))
+		w.WriteHeader(http.StatusOK)
+	})
+}
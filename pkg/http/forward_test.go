@@ -0,0 +1,83 @@
+package http_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/joshturge-io/auth/pkg/auth"
+	authhttp "github.com/joshturge-io/auth/pkg/http"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+type identifier struct{}
+
+func (id identifier) Identify(ctx context.Context, tokenStr string) (*auth.Identity, error) {
+	switch tokenStr {
+	case "valid":
+		return &auth.Identity{UserId: "user", Grants: token.Grants{
+			Roles:       []string{"admin", "user"},
+			Permissions: []string{"users:manage"},
+			PermLevel:   100,
+		}}, nil
+	case "unavailable":
+		return nil, errors.New("unable to reach repository")
+	}
+
+	return nil, token.ErrJWInvalid
+}
+
+func TestForwardAuthHandler(t *testing.T) {
+	handler := authhttp.ForwardAuthHandler(identifier{}, "access_token",
+		log.New(ioutil.Discard, "", 0))
+
+	tests := []struct {
+		name   string
+		header string
+		cookie string
+		status int
+	}{
+		{"bearer token", "Bearer valid", "", http.StatusOK},
+		{"cookie", "", "valid", http.StatusOK},
+		{"invalid token", "Bearer invalid", "", http.StatusUnauthorized},
+		{"other scheme", "Basic valid", "valid", http.StatusUnauthorized},
+		{"no token", "", "", http.StatusUnauthorized},
+		{"repository error", "Bearer unavailable", "", http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, authhttp.ForwardAuthPath, nil)
+		if test.header != "" {
+			req.Header.Set("Authorization", test.header)
+		}
+		if test.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: test.cookie})
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != test.status {
+			t.Errorf("%s: unexpected status wanted: %d got: %d", test.name, test.status,
+				rec.Code)
+			continue
+		}
+
+		if test.status != http.StatusOK {
+			if rec.Header().Get(authhttp.UserIdHeader) != "" {
+				t.Errorf("%s: identity headers set on a failed request", test.name)
+			}
+			continue
+		}
+
+		if rec.Header().Get(authhttp.UserIdHeader) != "user" ||
+			rec.Header().Get(authhttp.UserRolesHeader) != "admin,user" ||
+			rec.Header().Get(authhttp.UserLevelHeader) != "100" {
+			t.Errorf("%s: unexpected identity headers: %v", test.name, rec.Header())
+		}
+	}
+}
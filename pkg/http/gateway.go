@@ -0,0 +1,287 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	protobuf "github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Paths of the JSON gateway endpoints, each takes a POST with the JSON form of the request
+// message of the gRPC call it mirrors
+const (
+	LoginPath     = "/v1/login"
+	VerifyMFAPath = "/v1/mfa/verify"
+	RefreshPath   = "/v1/refresh"
+	ValidatePath  = "/v1/validate"
+	LogoutPath    = "/v1/logout"
+)
+
+// maxBodySize is the largest request body the gateway will read
+const maxBodySize = 1 << 20
+
+// servicePrefix is the full method prefix of calls passed to interceptors
+const servicePrefix = "/proto.auth.Authentication/"
+
+// GatewayOptions changes how the gateway serves calls
+type GatewayOptions struct {
+	// RefreshCookie sets the refresh token in a HttpOnly, Secure and SameSite cookie instead
+	// of the response body, Refresh and Logout read it from the cookie when it isn't in the
+	// request body
+	RefreshCookie bool
+	// CookieName of the refresh token cookie
+	CookieName string
+	// CookieDomain and CookiePath scope the cookie, the path defaults to the gateway paths
+	CookieDomain string
+	CookiePath   string
+	// Interceptors are run around every call in order, like they are by the gRPC server
+	Interceptors []grpc.UnaryServerInterceptor
+}
+
+// errorBody is the JSON body of a failed call
+type errorBody struct {
+	Code    string `json:"code"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message"`
+}
+
+type gateway struct {
+	srv proto.AuthenticationServer
+	opt *GatewayOptions
+}
+
+// RegisterGateway will register JSON endpoints mirroring the Login, VerifyMFA, Refresh,
+// ValidateJWT and Logout calls of srv on mux
+func RegisterGateway(mux *http.ServeMux, srv proto.AuthenticationServer, opt *GatewayOptions) {
+	if opt == nil {
+		opt = &GatewayOptions{}
+	}
+	if opt.CookieName == "" {
+		opt.CookieName = "refresh_token"
+	}
+	if opt.CookiePath == "" {
+		opt.CookiePath = "/v1/"
+	}
+
+	g := &gateway{srv, opt}
+	mux.HandleFunc(LoginPath, g.login)
+	mux.HandleFunc(VerifyMFAPath, g.verifyMFA)
+	mux.HandleFunc(RefreshPath, g.refresh)
+	mux.HandleFunc(ValidatePath, g.validate)
+	mux.HandleFunc(LogoutPath, g.logout)
+}
+
+// httpStatus will get the http status matching a grpc status code
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return http.StatusRequestTimeout
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// writeError will write a status error as JSON, a Retry-After header is set when the status
+// says when to retry
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	body := errorBody{Code: st.Code().String(), Message: st.Message()}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *proto.ErrorInfo:
+			body.Reason = detail.GetReason().String()
+		case *errdetails.RetryInfo:
+			if delay, err := ptypes.Duration(detail.GetRetryDelay()); err == nil {
+				w.Header().Set("Retry-After",
+					strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeMessage will write a message as JSON
+func writeMessage(w http.ResponseWriter, msg protobuf.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	marshaler := &jsonpb.Marshaler{EmitDefaults: true}
+	marshaler.Marshal(w, msg)
+}
+
+// callContext will create the context of a call holding the client address and user agent
+// like the context of a gRPC call
+func callContext(r *http.Request) context.Context {
+	ctx := metadata.NewIncomingContext(r.Context(),
+		metadata.Pairs("user-agent", r.UserAgent()))
+
+	addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr)
+	if err != nil {
+		return ctx
+	}
+
+	return peer.NewContext(ctx, &peer.Peer{Addr: addr})
+}
+
+// call will decode a request message from the body of r and pass it to handler through the
+// interceptors. An error is written and false returned when the call fails
+func (g *gateway) call(w http.ResponseWriter, r *http.Request, method string,
+	req protobuf.Message, handler grpc.UnaryHandler) (interface{}, bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed),
+			http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	unmarshaler := &jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err := unmarshaler.Unmarshal(http.MaxBytesReader(w, r.Body, maxBodySize),
+		req); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "failed to decode request: %s",
+			err.Error()))
+		return nil, false
+	}
+
+	if g.opt.RefreshCookie {
+		if sess, ok := req.(*proto.Session); ok && sess.GetRefreshToken() == "" {
+			if cookie, err := r.Cookie(g.opt.CookieName); err == nil {
+				sess.RefreshToken = cookie.Value
+			}
+		}
+	}
+
+	info := &grpc.UnaryServerInfo{Server: g.srv, FullMethod: servicePrefix + method}
+	for i := len(g.opt.Interceptors) - 1; i >= 0; i-- {
+		interceptor, next := g.opt.Interceptors[i], handler
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return interceptor(ctx, req, info, next)
+		}
+	}
+
+	resp, err := handler(callContext(r), req)
+	if err != nil {
+		writeError(w, err)
+		return nil, false
+	}
+
+	return resp, true
+}
+
+// writeSession will write a session, the refresh token is moved into a cookie when the
+// gateway is set to use one
+func (g *gateway) writeSession(w http.ResponseWriter, sess *proto.Session) {
+	if g.opt.RefreshCookie && sess.GetRefreshToken() != "" {
+		http.SetCookie(w, &http.Cookie{
+			Name:     g.opt.CookieName,
+			Value:    sess.GetRefreshToken(),
+			Path:     g.opt.CookiePath,
+			Domain:   g.opt.CookieDomain,
+			Expires:  time.Unix(sess.GetRefreshExpiration(), 0),
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		sess.RefreshToken = ""
+	}
+
+	writeMessage(w, sess)
+}
+
+func (g *gateway) login(w http.ResponseWriter, r *http.Request) {
+	resp, ok := g.call(w, r, "Login", &proto.Credentials{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.srv.Login(ctx, req.(*proto.Credentials))
+		})
+	if ok {
+		g.writeSession(w, resp.(*proto.Session))
+	}
+}
+
+func (g *gateway) verifyMFA(w http.ResponseWriter, r *http.Request) {
+	resp, ok := g.call(w, r, "VerifyMFA", &proto.MFAVerification{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.srv.VerifyMFA(ctx, req.(*proto.MFAVerification))
+		})
+	if ok {
+		g.writeSession(w, resp.(*proto.Session))
+	}
+}
+
+func (g *gateway) refresh(w http.ResponseWriter, r *http.Request) {
+	resp, ok := g.call(w, r, "Refresh", &proto.Session{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.srv.Refresh(ctx, req.(*proto.Session))
+		})
+	if ok {
+		g.writeSession(w, resp.(*proto.Session))
+	}
+}
+
+func (g *gateway) validate(w http.ResponseWriter, r *http.Request) {
+	resp, ok := g.call(w, r, "ValidateJWT", &proto.JWT{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.srv.ValidateJWT(ctx, req.(*proto.JWT))
+		})
+	if ok {
+		writeMessage(w, resp.(*proto.ValidityStatus))
+	}
+}
+
+func (g *gateway) logout(w http.ResponseWriter, r *http.Request) {
+	resp, ok := g.call(w, r, "Logout", &proto.Session{},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return g.srv.Logout(ctx, req.(*proto.Session))
+		})
+	if !ok {
+		return
+	}
+
+	if g.opt.RefreshCookie {
+		http.SetCookie(w, &http.Cookie{
+			Name:     g.opt.CookieName,
+			Path:     g.opt.CookiePath,
+			Domain:   g.opt.CookieDomain,
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			SameSite: http.SameSiteStrictMode,
+		})
+	}
+
+	writeMessage(w, resp.(*proto.LogoutStatus))
+}
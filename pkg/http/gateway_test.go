@@ -0,0 +1,150 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	authhttp "github.com/joshturge-io/auth/pkg/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type authServer struct {
+	proto.UnimplementedAuthenticationServer
+}
+
+func (as *authServer) Login(ctx context.Context, cred *proto.Credentials) (*proto.Session,
+	error) {
+
+	if cred.GetPassword() != "123password" {
+		st, _ := status.New(codes.Unauthenticated, "invalid username or password").WithDetails(
+			&proto.ErrorInfo{Reason: proto.ErrorReason_INVALID_CREDENTIALS})
+		return nil, st.Err()
+	}
+
+	return &proto.Session{
+		UserId:            cred.GetUsername(),
+		Jwt:               "jwt",
+		RefreshToken:      "refresh",
+		RefreshExpiration: time.Now().Add(time.Hour).Unix(),
+		SessionId:         "session",
+	}, nil
+}
+
+func (as *authServer) Refresh(ctx context.Context, sess *proto.Session) (*proto.Session,
+	error) {
+
+	if sess.GetRefreshToken() != "refresh" {
+		return nil, status.Error(codes.Unauthenticated, "session is not valid")
+	}
+
+	return &proto.Session{
+		UserId:            sess.GetUserId(),
+		Jwt:               "renewed_jwt",
+		RefreshToken:      "renewed_refresh",
+		RefreshExpiration: time.Now().Add(time.Hour).Unix(),
+		SessionId:         sess.GetSessionId(),
+	}, nil
+}
+
+func (as *authServer) ValidateJWT(ctx context.Context, jw *proto.JWT) (*proto.ValidityStatus,
+	error) {
+
+	return &proto.ValidityStatus{Valid: jw.GetToken() == "jwt"}, nil
+}
+
+// post will make a request to the gateway
+func post(handler http.Handler, path, body string,
+	cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestGateway(t *testing.T) {
+	var methods []string
+	mux := http.NewServeMux()
+	authhttp.RegisterGateway(mux, &authServer{}, &authhttp.GatewayOptions{
+		RefreshCookie: true,
+		Interceptors: []grpc.UnaryServerInterceptor{func(ctx context.Context, req interface{},
+			info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			methods = append(methods, info.FullMethod)
+			return handler(ctx, req)
+		}},
+	})
+
+	rec := post(mux, authhttp.LoginPath, `{"username": "user", "password": "123password"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status wanted: %d got: %d: %s", http.StatusOK, rec.Code,
+			rec.Body.String())
+	}
+
+	session := map[string]interface{}{}
+	if err := json.NewDecoder(rec.Body).Decode(&session); err != nil {
+		t.Fatal(err)
+	}
+
+	if session["jwt"] != "jwt" || session["sessionId"] != "session" {
+		t.Errorf("unexpected session: %v", session)
+	}
+
+	if token, ok := session["refreshToken"]; ok && token != "" {
+		t.Errorf("refresh token should only be set in the cookie got: %v", token)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value != "refresh" || !cookies[0].HttpOnly ||
+		!cookies[0].Secure || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("unexpected refresh cookie: %v", cookies)
+	}
+
+	rec = post(mux, authhttp.RefreshPath, `{"userId": "user", "sessionId": "session"}`,
+		cookies[0])
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected refresh with the cookie to succeed got: %d: %s", rec.Code,
+			rec.Body.String())
+	}
+
+	rec = post(mux, authhttp.LoginPath, `{"username": "user", "password": "wrong"}`)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unexpected status wanted: %d got: %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	body := map[string]string{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body["code"] != "Unauthenticated" || body["reason"] != "INVALID_CREDENTIALS" {
+		t.Errorf("unexpected error body: %v", body)
+	}
+
+	rec = post(mux, authhttp.ValidatePath, `{"token": "other"}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"valid":false`) {
+		t.Errorf("unexpected validation response: %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if rec = post(mux, authhttp.LoginPath, `{"username": `); rec.Code !=
+		http.StatusBadRequest {
+		t.Errorf("unexpected status wanted: %d got: %d", http.StatusBadRequest, rec.Code)
+	}
+
+	want := []string{"/proto.auth.Authentication/Login", "/proto.auth.Authentication/Refresh",
+		"/proto.auth.Authentication/Login", "/proto.auth.Authentication/ValidateJWT"}
+	if strings.Join(methods, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected intercepted methods wanted: %v got: %v", want, methods)
+	}
+}
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// JWKSPath is where the jwks document is served
+const JWKSPath = "/.well-known/jwks.json"
+
+// KeySetter gets the public keys jwts can be verified with
+type KeySetter interface {
+	KeySet() *token.JWKS
+}
+
+// JWKSHandler will serve the public keys of ks as a jwks document
+func JWKSHandler(ks KeySetter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed),
+				http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		json.NewEncoder(w).Encode(ks.KeySet())
+	})
+}
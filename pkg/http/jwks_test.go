@@ -0,0 +1,47 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authhttp "github.com/joshturge-io/auth/pkg/http"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+type keySet token.JWKS
+
+func (ks *keySet) KeySet() *token.JWKS {
+	return (*token.JWKS)(ks)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	ks := &keySet{Keys: []*token.JWK{{Kty: "OKP", Kid: "kid", Use: "sig", Alg: "EdDSA",
+		Crv: "Ed25519", X: "x"}}}
+
+	rec := httptest.NewRecorder()
+	authhttp.JWKSHandler(ks).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		authhttp.JWKSPath, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status wanted: %d got: %d", http.StatusOK, rec.Code)
+	}
+
+	jwks := &token.JWKS{}
+	if err := json.NewDecoder(rec.Body).Decode(jwks); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(jwks.Keys) != 1 || *jwks.Keys[0] != *ks.Keys[0] {
+		t.Errorf("unexpected key set: %+v", jwks.Keys)
+	}
+
+	rec = httptest.NewRecorder()
+	authhttp.JWKSHandler(ks).ServeHTTP(rec, httptest.NewRequest(http.MethodPost,
+		authhttp.JWKSPath, nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status wanted: %d got: %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Server is a http server
+type Server struct {
+	hs       *http.Server
+	listener net.Listener
+	serveErr error
+}
+
+// NewServer will create a new listener and server with a handler
+func NewServer(addr string, handler http.Handler) (*Server, error) {
+	var (
+		srv = &Server{hs: &http.Server{Handler: handler}}
+		err error
+	)
+	srv.listener, err = net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen to address: %s: %w", addr, err)
+	}
+
+	return srv, nil
+}
+
+// Serve will start serving the http server, errors can be checked through the Err method
+func (s *Server) Serve() {
+	go func() {
+		if err := s.hs.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.serveErr = err
+		}
+	}()
+}
+
+// Err will return any errors that accured while serving, returns nil when none
+func (s *Server) Err() error {
+	return s.serveErr
+}
+
+// Close will gracefully shutdown the http server, returns an error if context is done
+func (s *Server) Close(ctx context.Context) error {
+	if err := s.hs.Shutdown(ctx); err != nil {
+		s.hs.Close()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// bearerPrefix is the scheme of authorization metadata holding a jwt
+const bearerPrefix = "bearer "
+
+// authenticate will validate the jwt in the authorization metadata of a call and put the
+// identity of the user in the context
+func (v *Validator) authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || len(values[0]) <= len(bearerPrefix) ||
+		!strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "bearer token not provided")
+	}
+
+	identity, err := v.Validate(ctx, strings.TrimSpace(values[0][len(bearerPrefix):]))
+	if err != nil {
+		if isTokenError(err) {
+			return nil, status.Error(codes.Unauthenticated, "token is not valid")
+		}
+		return nil, status.Error(codes.Unavailable, "unable to validate token")
+	}
+
+	return NewContext(ctx, identity), nil
+}
+
+// isPublic will check if a method can be called without a jwt
+func (v *Validator) isPublic(fullMethod string) bool {
+	for _, method := range v.opt.PublicMethods {
+		if method == fullMethod {
+			return true
+		}
+	}
+
+	return false
+}
+
+// UnaryServerInterceptor will only let calls with a valid jwt through, the identity of the
+// user is put in the context of the call
+func (v *Validator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		if v.isPublic(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := v.authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// serverStream is a server stream with the context of an authenticated call
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+// StreamServerInterceptor will only let streams with a valid jwt through, the identity of the
+// user is put in the context of the stream
+func (v *Validator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+
+		if v.isPublic(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := v.authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ss, ctx})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	authhttp "github.com/joshturge-io/auth/pkg/http"
+)
+
+// Handler will only pass requests with a valid jwt on to next, the identity of the user is
+// put in the request context. Requests without a valid jwt get a 401, and a 503 is returned
+// when the jwt can't be checked with the authentication service
+func (v *Validator) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenStr := authhttp.BearerToken(r, v.opt.CookieName)
+		if tokenStr == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="auth"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		identity, err := v.Validate(r.Context(), tokenStr)
+		if err != nil {
+			if isTokenError(err) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="auth", error="invalid_token"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized),
+					http.StatusUnauthorized)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable),
+				http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), identity)))
+	})
+}
@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// ErrRevoked is returned when the authentication service says a jwt is no longer valid
+var ErrRevoked = fmt.Errorf("token has been revoked: %w", token.ErrJWInvalid)
+
+// defaultCacheSize is how many revocation results are cached at once when no size is set
+const defaultCacheSize = 10000
+
+// Identity of the user an incoming jwt was issued to
+type Identity struct {
+	UserId string
+	token.Grants
+	// Token holds the validated jwt and its claims
+	Token *token.JW
+}
+
+type identityKey struct{}
+
+// NewContext will create a context holding an identity
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext will get the identity of an authenticated request from its context
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// Options of a validator
+type Options struct {
+	// JWTOptions are the issuer, audience and leeway jwts are validated against, can be nil
+	JWTOptions *token.Options
+	// Remote checks jwts haven't been revoked through the ValidateJWT call, jwts are only
+	// validated locally when nil
+	Remote proto.AuthenticationClient
+	// CacheTTL is how long the result of a ValidateJWT call is cached
+	CacheTTL time.Duration
+	// CacheSize is the most ValidateJWT results cached at once, defaults to 10000
+	CacheSize int
+	// CookieName of the cookie the http middleware reads jwts from when the request doesn't
+	// have an Authorization header, cookies aren't read when empty
+	CookieName string
+	// PublicMethods are full grpc methods the interceptors let through without a jwt
+	PublicMethods []string
+}
+
+type cacheEntry struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+// Validator validates the signature and claims of jwts locally, and optionally checks they
+// haven't been revoked with the authentication service
+type Validator struct {
+	verifier token.Verifier
+	opt      *Options
+
+	mu    sync.Mutex
+	cache map[string]cacheEntry
+}
+
+// NewValidator will create a validator verifying jwts with the keys of v
+func NewValidator(v token.Verifier, opt *Options) *Validator {
+	if opt == nil {
+		opt = &Options{}
+	}
+	if opt.CacheTTL == 0 {
+		opt.CacheTTL = 10 * time.Second
+	}
+	if opt.CacheSize <= 0 {
+		opt.CacheSize = defaultCacheSize
+	}
+
+	return &Validator{verifier: v, opt: opt, cache: make(map[string]cacheEntry)}
+}
+
+// cached will get a cached revocation result of a jwt
+func (v *Validator) cached(key string) (valid, ok bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+
+	return entry.valid, true
+}
+
+// store will cache a revocation result of a jwt until the ttl passes or the jwt expires. When
+// the cache is full expired results are swept, and if none have expired the result closest to
+// expiring is evicted
+func (v *Validator) store(key string, valid bool, exp time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := v.cache[key]; !ok && len(v.cache) >= v.opt.CacheSize {
+		var next string
+		for k, entry := range v.cache {
+			if now.After(entry.expiresAt) {
+				delete(v.cache, k)
+			} else if next == "" || entry.expiresAt.Before(v.cache[next].expiresAt) {
+				next = k
+			}
+		}
+
+		if len(v.cache) >= v.opt.CacheSize {
+			delete(v.cache, next)
+		}
+	}
+
+	expiresAt := now.Add(v.opt.CacheTTL)
+	if exp.Before(expiresAt) {
+		expiresAt = exp
+	}
+
+	v.cache[key] = cacheEntry{valid, expiresAt}
+}
+
+// isRevoked will check if the authentication service no longer accepts a jwt
+func (v *Validator) isRevoked(ctx context.Context, jw *token.JW) (bool, error) {
+	key := jw.Id()
+	if key == "" {
+		key = jw.Token()
+	}
+
+	if valid, ok := v.cached(key); ok {
+		return !valid, nil
+	}
+
+	status, err := v.opt.Remote.ValidateJWT(ctx, &proto.JWT{Token: jw.Token()})
+	if err != nil {
+		return false, fmt.Errorf("failed to validate token remotely: %w", err)
+	}
+
+	v.store(key, status.GetValid(), time.Now().Add(jw.ExpiresIn()))
+
+	return !status.GetValid(), nil
+}
+
+// Validate will verify a jwt, check it hasn't expired and, when a remote is set, that it
+// hasn't been revoked. Returns an error wrapping token.ErrJWInvalid or token.ErrJWExpired
+// when the jwt isn't valid
+func (v *Validator) Validate(ctx context.Context, tokenStr string) (*Identity, error) {
+	jw, err := token.NewJWFromExisting(v.verifier, v.opt.JWTOptions, tokenStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if jw.IsExpired() {
+		return nil, token.ErrJWExpired
+	}
+
+	if v.opt.Remote != nil {
+		revoked, err := v.isRevoked(ctx, jw)
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return nil, ErrRevoked
+		}
+	}
+
+	return &Identity{UserId: jw.Username(), Grants: jw.Grants(), Token: jw}, nil
+}
+
+// isTokenError will check if an error was caused by the jwt rather than by validating it
+func isTokenError(err error) bool {
+	return errors.Is(err, token.ErrJWInvalid) || errors.Is(err, token.ErrJWExpired)
+}
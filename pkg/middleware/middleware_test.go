@@ -0,0 +1,234 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	proto "github.com/joshturge-io/auth/pkg/grpc/proto"
+	"github.com/joshturge-io/auth/pkg/middleware"
+	"github.com/joshturge-io/auth/pkg/token"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var jwtKey = token.NewHMACKey("secret")
+
+// newToken will create a jwt signed by key for a user with a role
+func newToken(t *testing.T, key *token.Key, exp time.Duration) string {
+	t.Helper()
+	jw := token.NewJW(key, nil, "user", exp)
+	jw.SetGrants(token.Grants{Roles: []string{"user"}, PermLevel: 10})
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	return jw.Token()
+}
+
+type remote struct {
+	proto.AuthenticationClient
+	calls   int32
+	revoked string
+	err     error
+}
+
+func (r *remote) ValidateJWT(ctx context.Context, in *proto.JWT,
+	opts ...grpc.CallOption) (*proto.ValidityStatus, error) {
+	atomic.AddInt32(&r.calls, 1)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &proto.ValidityStatus{Valid: in.GetToken() != r.revoked}, nil
+}
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	rem := &remote{}
+	v := middleware.NewValidator(jwtKey, &middleware.Options{Remote: rem, CacheTTL: time.Minute})
+
+	valid := newToken(t, jwtKey, time.Minute)
+	for i := 0; i < 3; i++ {
+		identity, err := v.Validate(ctx, valid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if identity.UserId != "user" || identity.PermLevel != 10 {
+			t.Errorf("unexpected identity: %+v", identity)
+		}
+	}
+
+	if calls := atomic.LoadInt32(&rem.calls); calls != 1 {
+		t.Errorf("expected the remote result to be cached got: %d calls", calls)
+	}
+
+	rem.revoked = newToken(t, jwtKey, time.Minute)
+	if _, err := v.Validate(ctx, rem.revoked); !errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected revoked token to be invalid got: %v", err)
+	}
+
+	expired := newToken(t, jwtKey, -time.Minute)
+	if _, err := v.Validate(ctx, expired); !errors.Is(err, token.ErrJWExpired) {
+		t.Errorf("expected ErrJWExpired got: %v", err)
+	}
+
+	other := newToken(t, token.NewHMACKey("other_secret"), time.Minute)
+	if _, err := v.Validate(ctx, other); !errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected token signed by another key to be invalid got: %v", err)
+	}
+
+	rem.err = status.Error(codes.Unavailable, "connection refused")
+	_, err := v.Validate(ctx, newToken(t, jwtKey, time.Minute))
+	if err == nil || errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected remote failure got: %v", err)
+	}
+}
+
+func TestValidateCacheSize(t *testing.T) {
+	ctx := context.Background()
+	rem := &remote{}
+	v := middleware.NewValidator(jwtKey, &middleware.Options{
+		Remote:    rem,
+		CacheTTL:  time.Hour,
+		CacheSize: 2,
+	})
+
+	// results are cached until the jwts expire, so they expire in the order of the tokens
+	tokens := make([]string, 3)
+	for i := range tokens {
+		tokens[i] = newToken(t, jwtKey, time.Duration(i+1)*time.Minute)
+	}
+
+	// each validation of an uncached jwt calls the remote and evicts the result closest to
+	// expiring once two are cached
+	for i, idx := range []int{0, 1, 2, 1, 2, 0, 2, 1} {
+		if _, err := v.Validate(ctx, tokens[idx]); err != nil {
+			t.Fatal(err)
+		}
+
+		want := []int32{1, 2, 3, 3, 3, 4, 4, 5}[i]
+		if calls := atomic.LoadInt32(&rem.calls); calls != want {
+			t.Errorf("validation %d: expected %d remote calls got: %d", i, want, calls)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	v := middleware.NewValidator(jwtKey, &middleware.Options{CookieName: "access_token"})
+	handler := v.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := middleware.FromContext(r.Context())
+		if !ok {
+			t.Error("expected identity in the request context")
+			return
+		}
+		w.Write([]byte(identity.UserId))
+	}))
+
+	valid := newToken(t, jwtKey, time.Minute)
+	tests := []struct {
+		name   string
+		header string
+		cookie string
+		status int
+	}{
+		{"bearer token", "Bearer " + valid, "", http.StatusOK},
+		{"cookie", "", valid, http.StatusOK},
+		{"expired token", "Bearer " + newToken(t, jwtKey, -time.Minute), "",
+			http.StatusUnauthorized},
+		{"no token", "", "", http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if test.header != "" {
+			req.Header.Set("Authorization", test.header)
+		}
+		if test.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "access_token", Value: test.cookie})
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != test.status {
+			t.Errorf("%s: unexpected status wanted: %d got: %d", test.name, test.status,
+				rec.Code)
+		}
+
+		if test.status == http.StatusOK && rec.Body.String() != "user" {
+			t.Errorf("%s: unexpected body: %s", test.name, rec.Body.String())
+		}
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss *serverStream) Context() context.Context {
+	return ss.ctx
+}
+
+func TestInterceptors(t *testing.T) {
+	v := middleware.NewValidator(jwtKey, &middleware.Options{
+		PublicMethods: []string{"/grpc.health.v1.Health/Check"},
+	})
+
+	authed := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("authorization", "Bearer "+newToken(t, jwtKey, time.Minute)))
+	unauthed := context.Background()
+
+	unary := v.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		identity, ok := middleware.FromContext(ctx)
+		if !ok {
+			return nil, errors.New("identity not in context")
+		}
+		return identity.UserId, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/app.Service/Method"}
+
+	if resp, err := unary(authed, nil, info, handler); err != nil || resp != "user" {
+		t.Errorf("expected call to be authenticated got: %v: %v", resp, err)
+	}
+
+	if _, err := unary(unauthed, nil, info, handler); status.Code(err) !=
+		codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated got: %v", err)
+	}
+
+	health := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	if _, err := unary(unauthed, nil, health, func(ctx context.Context,
+		req interface{}) (interface{}, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("expected public method to be let through got: %v", err)
+	}
+
+	stream := v.StreamServerInterceptor()
+	streamHandler := func(srv interface{}, ss grpc.ServerStream) error {
+		if _, ok := middleware.FromContext(ss.Context()); !ok {
+			return errors.New("identity not in stream context")
+		}
+		return nil
+	}
+	streamInfo := &grpc.StreamServerInfo{FullMethod: "/app.Service/Stream"}
+
+	if err := stream(nil, &serverStream{ctx: authed}, streamInfo, streamHandler); err != nil {
+		t.Errorf("expected stream to be authenticated got: %v", err)
+	}
+
+	if err := stream(nil, &serverStream{ctx: unauthed}, streamInfo,
+		streamHandler); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated got: %v", err)
+	}
+}
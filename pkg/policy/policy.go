@@ -0,0 +1,185 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/joshturge-io/auth/pkg/token"
+	"gopkg.in/yaml.v2"
+)
+
+var ErrInvalidPolicy = errors.New("policy is not valid")
+
+// SubjectVar is replaced by the subject of the token in resource patterns
+const SubjectVar = "${sub}"
+
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Rule allows or denies actions on resources to tokens that match all of its conditions.
+// Actions and resources are matched with path.Match patterns
+type Rule struct {
+	Name      string   `yaml:"name"`
+	Effect    Effect   `yaml:"effect"`
+	Actions   []string `yaml:"actions"`
+	Resources []string `yaml:"resources"`
+	// token must have at least one of the roles when set
+	Roles []string `yaml:"roles"`
+	// token must have every permission when set
+	Permissions []string `yaml:"permissions"`
+	// token must have a permission level of at least MinLevel
+	MinLevel int `yaml:"minlevel"`
+}
+
+// Policy is a set of rules, a deny rule always overrides an allow rule and requests that no
+// rule matches are denied
+type Policy struct {
+	Rules []*Rule `yaml:"rules"`
+}
+
+// Request is an action a token wants to perform on a resource
+type Request struct {
+	Subject  string
+	Grants   token.Grants
+	Action   string
+	Resource string
+}
+
+// Decision is the outcome of evaluating a request against a policy
+type Decision struct {
+	Allowed bool
+	Reason  string
+}
+
+// Parse will parse a YAML policy and make sure its rules are valid
+func Parse(data []byte) (*Policy, error) {
+	p := &Policy{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy: %s: %w", err, ErrInvalidPolicy)
+	}
+
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i)
+		}
+
+		if rule.Effect != Allow && rule.Effect != Deny {
+			return nil, fmt.Errorf("%s: effect must be allow or deny got: %s: %w", rule.Name,
+				rule.Effect, ErrInvalidPolicy)
+		}
+
+		if len(rule.Actions) == 0 || len(rule.Resources) == 0 {
+			return nil, fmt.Errorf("%s: actions and resources are required: %w", rule.Name,
+				ErrInvalidPolicy)
+		}
+
+		for _, patterns := range [][]string{rule.Actions, rule.Resources} {
+			for _, pattern := range patterns {
+				if _, err := path.Match(pattern, pattern); err != nil {
+					return nil, fmt.Errorf("%s: %s: %s: %w", rule.Name, pattern, err,
+						ErrInvalidPolicy)
+				}
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// Load will read and parse a YAML policy file
+func Load(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read policy: %w", err)
+	}
+
+	return Parse(data)
+}
+
+// Evaluate will decide if a request is allowed by the policy
+func (p *Policy) Evaluate(req *Request) *Decision {
+	var allowedBy *Rule
+	for _, rule := range p.Rules {
+		if !rule.matches(req) {
+			continue
+		}
+
+		if rule.Effect == Deny {
+			return &Decision{Reason: "denied by " + rule.Name}
+		}
+
+		if allowedBy == nil {
+			allowedBy = rule
+		}
+	}
+
+	if allowedBy == nil {
+		return &Decision{Reason: "no rule allows the action on the resource"}
+	}
+
+	return &Decision{Allowed: true, Reason: "allowed by " + allowedBy.Name}
+}
+
+// matches will check if a request satisfies every condition of the rule
+func (r *Rule) matches(req *Request) bool {
+	if !matchAny(r.Actions, req.Action, "") ||
+		!matchAny(r.Resources, req.Resource, req.Subject) {
+		return false
+	}
+
+	if req.Grants.PermLevel < r.MinLevel {
+		return false
+	}
+
+	if len(r.Roles) > 0 && !containsAny(req.Grants.Roles, r.Roles) {
+		return false
+	}
+
+	for _, perm := range r.Permissions {
+		if !containsAny(req.Grants.Permissions, []string{perm}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchAny will check if a value matches any of the patterns, the subject variable in a pattern
+// is replaced with subject
+func matchAny(patterns []string, value, subject string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(pattern, SubjectVar) {
+			// a subject could contain pattern characters
+			if subject == "" || strings.ContainsAny(subject, `*?[\/`) {
+				continue
+			}
+			pattern = strings.ReplaceAll(pattern, SubjectVar, subject)
+		}
+
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsAny will check if any of the wanted values are in values
+func containsAny(values, wanted []string) bool {
+	for _, want := range wanted {
+		for _, value := range values {
+			if value == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
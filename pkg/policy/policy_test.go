@@ -0,0 +1,78 @@
+package policy_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/joshturge-io/auth/pkg/policy"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+var testPolicy = []byte(`
+rules:
+    - name: "admins"
+      effect: "allow"
+      actions: ["users:*"]
+      resources: ["users/*"]
+      roles: ["admin"]
+      minlevel: 50
+    - name: "own profile"
+      effect: "allow"
+      actions: ["users:read"]
+      resources: ["users/${sub}"]
+    - name: "root"
+      effect: "deny"
+      actions: ["users:delete"]
+      resources: ["users/root"]
+`)
+
+func TestEvaluate(t *testing.T) {
+	p, err := policy.Parse(testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := token.Grants{Roles: []string{"admin"}, PermLevel: 100}
+	for _, test := range []struct {
+		req     *policy.Request
+		allowed bool
+	}{
+		{&policy.Request{"alice", admin, "users:delete", "users/bob"}, true},
+		{&policy.Request{"alice", admin, "users:delete", "users/root"}, false},
+		{&policy.Request{"alice", token.Grants{Roles: []string{"admin"}}, "users:delete",
+			"users/bob"}, false},
+		{&policy.Request{"bob", token.Grants{}, "users:read", "users/bob"}, true},
+		{&policy.Request{"bob", token.Grants{}, "users:read", "users/alice"}, false},
+		{&policy.Request{"*", token.Grants{}, "users:read", "users/alice"}, false},
+		{&policy.Request{"bob", token.Grants{}, "users:update", "users/bob"}, false},
+	} {
+		decision := p.Evaluate(test.req)
+		if decision.Allowed != test.allowed {
+			t.Errorf("%+v: wanted allowed: %t got: %t: %s", test.req, test.allowed,
+				decision.Allowed, decision.Reason)
+		}
+
+		if decision.Reason == "" {
+			t.Errorf("%+v: decision has no reason", test.req)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, data := range []string{
+		`rules: [{effect: "maybe", actions: ["a"], resources: ["r"]}]`,
+		`rules: [{effect: "allow", resources: ["r"]}]`,
+		`rules: [{effect: "allow", actions: ["["], resources: ["r"]}]`,
+		`rules: [{effect: "allow", actions: ["a"], resources: ["r"], unknown: true}]`,
+	} {
+		if _, err := policy.Parse([]byte(data)); !errors.Is(err, policy.ErrInvalidPolicy) {
+			t.Errorf("%s: expected ErrInvalidPolicy got: %v", data, err)
+		}
+	}
+}
+
+func TestLoadExample(t *testing.T) {
+	if _, err := policy.Load("../../config/policy.yml"); err != nil {
+		t.Error(err)
+	}
+}
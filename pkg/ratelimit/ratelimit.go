@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is the token bucket a key is limited by
+type Limit struct {
+	// Rate tokens are added to the bucket at (per second), no limit when zero
+	Rate float64
+	// Burst is the most tokens the bucket can hold
+	Burst int
+}
+
+// Unlimited will check if the limit lets every call through
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// refillTime is how long an empty bucket takes to fill up again
+func (l Limit) refillTime() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+// Limiter takes tokens from the buckets of keys
+type Limiter interface {
+	// Allow will take a token from the bucket of a key, returns false along with how long
+	// until a token is available when the bucket is empty
+	Allow(key string, limit Limit) (bool, time.Duration, error)
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// how long the bucket takes to fill up from empty
+	refill time.Duration
+}
+
+// memoryLimiter keeps the buckets of every key in memory
+type memoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// sweepInterval is how often full buckets are removed from a memory limiter
+const sweepInterval = time.Minute
+
+// NewMemoryLimiter will create a limiter that keeps its buckets in memory, buckets aren't
+// shared between replicas
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{buckets: map[string]*bucket{}, lastSweep: time.Now()}
+}
+
+func (ml *memoryLimiter) Allow(key string, limit Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	now := time.Now()
+	ml.sweep(now)
+
+	b, ok := ml.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		ml.buckets[key] = b
+	}
+	b.refill = limit.refillTime()
+
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+
+	return false, time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second)), nil
+}
+
+// sweep will remove buckets that have had time to fill up again, they are recreated full the
+// next time their key is used
+func (ml *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(ml.lastSweep) < sweepInterval {
+		return
+	}
+	ml.lastSweep = now
+
+	for key, b := range ml.buckets {
+		if now.Sub(b.last) > b.refill {
+			delete(ml.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter()
+	limit := ratelimit.Limit{Rate: 1, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		allowed, _, err := limiter.Allow("key", limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !allowed {
+			t.Errorf("call %d should be allowed within the burst", i)
+		}
+	}
+
+	allowed, wait, err := limiter.Allow("key", limit)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if allowed || wait <= 0 || wait > time.Second {
+		t.Errorf("expected call over the burst to wait up to a second got: %t %s", allowed, wait)
+	}
+
+	// other keys have their own bucket
+	if allowed, _, _ = limiter.Allow("other", limit); !allowed {
+		t.Error("call with another key should be allowed")
+	}
+
+	for i := 0; i < 10; i++ {
+		if allowed, _, _ = limiter.Allow("key", ratelimit.Limit{}); !allowed {
+			t.Error("calls without a rate should be unlimited")
+		}
+	}
+}
+
+func TestMemoryLimiterRefill(t *testing.T) {
+	limiter := ratelimit.NewMemoryLimiter()
+	limit := ratelimit.Limit{Rate: 100, Burst: 1}
+
+	if allowed, _, _ := limiter.Allow("key", limit); !allowed {
+		t.Fatal("first call should be allowed")
+	}
+
+	if allowed, _, _ := limiter.Allow("key", limit); allowed {
+		t.Fatal("second call should be limited")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if allowed, _, _ := limiter.Allow("key", limit); !allowed {
+		t.Error("call should be allowed once the bucket refills")
+	}
+}
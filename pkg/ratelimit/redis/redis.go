@@ -0,0 +1,65 @@
+package redis
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+)
+
+// takeToken will refill a token bucket for the time since it was last used and take a token
+// from it, returns whether a token was taken and how many milliseconds until one is available
+var takeToken = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "last")
+local tokens = tonumber(bucket[1]) or burst
+local last = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - last) * rate / 1000)
+local allowed, wait = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call("HMSET", KEYS[1], "tokens", tostring(tokens), "last", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, wait}
+`)
+
+// redisLimiter keeps token buckets in redis so they are shared between replicas
+type redisLimiter struct {
+	*redis.Client
+}
+
+// NewRedisLimiter creates a new Limiter for a redis server
+func NewRedisLimiter(client *redis.Client) ratelimit.Limiter {
+	return &redisLimiter{client}
+}
+
+func (rl *redisLimiter) Allow(key string, limit ratelimit.Limit) (bool, time.Duration, error) {
+	if limit.Unlimited() {
+		return true, 0, nil
+	}
+
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	res, err := takeToken.Run(rl.Client, []string{strings.Join([]string{"ratelimit", key},
+		":")}, limit.Rate, limit.Burst, now).Result()
+	if err != nil {
+		return false, 0, fmt.Errorf("unable to take token: %w", err)
+	}
+
+	values, ok := res.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected result from token bucket: %v", res)
+	}
+
+	allowed, _ := values[0].(int64)
+	wait, _ := values[1].(int64)
+
+	return allowed == 1, time.Duration(wait) * time.Millisecond, nil
+}
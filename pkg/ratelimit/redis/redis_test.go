@@ -0,0 +1,50 @@
+package redis_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/joshturge-io/auth/pkg/ratelimit"
+	redisLimiter "github.com/joshturge-io/auth/pkg/ratelimit/redis"
+)
+
+var limiter ratelimit.Limiter
+
+func init() {
+	limiter = redisLimiter.NewRedisLimiter(redis.NewClient(&redis.Options{
+		Addr:     os.Getenv("REPO_ADDR"),
+		Password: os.Getenv("REPO_PSWD"),
+		DB:       0,
+	}))
+}
+
+func TestAllow(t *testing.T) {
+	key := "test_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	limit := ratelimit.Limit{Rate: 0.5, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		allowed, _, err := limiter.Allow(key, limit)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if !allowed {
+			t.Errorf("call %d should be allowed within the burst", i)
+		}
+	}
+
+	allowed, wait, err := limiter.Allow(key, limit)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if allowed || wait <= time.Second || wait > 2*time.Second {
+		t.Errorf("expected call over the burst to wait up to two seconds got: %t %s", allowed,
+			wait)
+	}
+}
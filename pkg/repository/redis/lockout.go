@@ -0,0 +1,116 @@
+package redis
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/joshturge-io/auth/pkg/repository"
+)
+
+// fmtFailuresKey will format the key of the sorted set holding the failed attempts of a
+// subject scored by the millisecond they happened at
+func (rks *redisKeyStore) fmtFailuresKey(subject string) string {
+	return strings.Join([]string{"failures", subject}, ":")
+}
+
+// fmtLockoutKey will format the key holding the time a users account is locked until
+func (rks *redisKeyStore) fmtLockoutKey(userId string) string {
+	return strings.Join([]string{"lockout", strings.TrimPrefix(userId, "user:")}, ":")
+}
+
+// unixMilli will get the milliseconds since the unix epoch of a time
+func unixMilli(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func (rks *redisKeyStore) GetFailures(subject string,
+	window time.Duration) (*repository.Failures, error) {
+	key := rks.fmtFailuresKey(subject)
+	min := strconv.FormatInt(unixMilli(time.Now().Add(-window)), 10)
+
+	var (
+		countCmd *redis.IntCmd
+		lastCmd  *redis.ZSliceCmd
+	)
+	_, err := rks.client.Pipelined(func(pipe redis.Pipeliner) error {
+		countCmd = pipe.ZCount(key, "("+min, "+inf")
+		lastCmd = pipe.ZRevRangeWithScores(key, 0, 0)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	failures := &repository.Failures{Count: int(countCmd.Val())}
+	if last := lastCmd.Val(); failures.Count > 0 && len(last) > 0 {
+		ms := int64(last[0].Score)
+		failures.Last = time.Unix(0, ms*int64(time.Millisecond))
+	}
+
+	return failures, nil
+}
+
+func (rks *redisKeyStore) GetLockout(userId string) (time.Time, error) {
+	until, err := rks.client.Get(rks.fmtLockoutKey(userId)).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	if lockedUntil := time.Unix(until, 0); lockedUntil.After(time.Now()) {
+		return lockedUntil, nil
+	}
+
+	return time.Time{}, nil
+}
+
+func (rks *redisKeyStore) AddFailure(subject string, at time.Time,
+	window time.Duration) (*repository.Failures, error) {
+	// members need to be unique so failures in the same millisecond are all counted
+	nonce := make([]byte, 4)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("could not generate failure nonce: %w", err)
+	}
+
+	key := rks.fmtFailuresKey(subject)
+	ms := unixMilli(at)
+
+	var countCmd *redis.IntCmd
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(key, "-inf", strconv.FormatInt(unixMilli(at.Add(-window)), 10))
+		pipe.ZAdd(key, redis.Z{
+			Score:  float64(ms),
+			Member: strconv.FormatInt(ms, 10) + "-" + hex.EncodeToString(nonce),
+		})
+		countCmd = pipe.ZCard(key)
+		pipe.PExpire(key, window)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.Failures{Count: int(countCmd.Val()), Last: at}, nil
+}
+
+func (rks *redisKeyStore) ClearFailures(subject string) error {
+	return rks.client.Del(rks.fmtFailuresKey(subject)).Err()
+}
+
+func (rks *redisKeyStore) SetLockout(userId string, until time.Time) error {
+	return rks.client.Set(rks.fmtLockoutKey(userId), until.Unix(), time.Until(until)).Err()
+}
+
+func (rks *redisKeyStore) RemoveLockout(userId string) error {
+	return rks.client.Del(rks.fmtLockoutKey(userId)).Err()
+}
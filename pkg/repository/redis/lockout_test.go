@@ -0,0 +1,82 @@
+package redis_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFailures(t *testing.T) {
+	subject := "user:test_user_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	now := time.Now()
+
+	// failures outside of the window are forgotten
+	for i, at := range []time.Time{now.Add(-2 * time.Minute), now, now} {
+		failures, err := repo.AddFailure(subject, at, time.Minute)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if want := []int{1, 1, 2}[i]; failures.Count != want {
+			t.Errorf("failure count does not match wanted: %d got: %d", want, failures.Count)
+		}
+	}
+
+	failures, err := repo.GetFailures(subject, time.Minute)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if failures.Count != 2 || failures.Last.Unix() != now.Unix() {
+		t.Errorf("unexpected failures: %+v", failures)
+	}
+
+	if err = repo.ClearFailures(subject); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if failures, err = repo.GetFailures(subject, time.Minute); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if failures.Count != 0 || !failures.Last.IsZero() {
+		t.Errorf("failures were not cleared: %+v", failures)
+	}
+}
+
+func TestLockout(t *testing.T) {
+	userId := "test_user_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	until := time.Now().Add(time.Minute)
+	if err := repo.SetLockout(userId, until); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	locked, err := repo.GetLockout(userId)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if locked.Unix() != until.Unix() {
+		t.Errorf("lockout does not match wanted: %s got: %s", until, locked)
+	}
+
+	if err = repo.RemoveLockout(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if locked, err = repo.GetLockout(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if !locked.IsZero() {
+		t.Errorf("lockout was not removed: %s", locked)
+	}
+}
@@ -0,0 +1,228 @@
+package redis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/joshturge-io/auth/pkg/repository"
+)
+
+// useTOTPStep will set the last accepted totp step of a user only if the new step is after it
+var useTOTPStep = redis.NewScript(`
+local last = tonumber(redis.call("HGET", KEYS[1], "totp_step") or "-1")
+if tonumber(ARGV[1]) <= last then
+	return 0
+end
+redis.call("HSET", KEYS[1], "totp_step", ARGV[1])
+return 1
+`)
+
+// incrMFAChallenge will increment the attempts of an mfa challenge only if it still exists so
+// an expired challenge isn't recreated without an expiry
+var incrMFAChallenge = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+// fmtMFAChallengeKey will format the key of an mfa challenge
+func (rks *redisKeyStore) fmtMFAChallengeKey(id string) string {
+	return strings.Join([]string{"mfa", id}, ":")
+}
+
+// fmtRecoveryKey will format the key of the set holding the recovery code hashes of a user
+func (rks *redisKeyStore) fmtRecoveryKey(userId string) string {
+	return strings.Join([]string{"recovery", strings.TrimPrefix(userId, "user:")}, ":")
+}
+
+func (rks *redisKeyStore) GetMFA(userId string) (*repository.MFA, error) {
+	var (
+		fieldsCmd *redis.SliceCmd
+		countCmd  *redis.IntCmd
+	)
+	_, err := rks.client.Pipelined(func(pipe redis.Pipeliner) error {
+		fieldsCmd = pipe.HMGet(rks.fmtUserId(userId), "totp", "totp_pending", "totp_step",
+			"recovery_salt")
+		countCmd = pipe.SCard(rks.fmtRecoveryKey(userId))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields := fieldsCmd.Val()
+	mfa := &repository.MFA{LastStep: -1, RecoveryCodes: int(countCmd.Val())}
+	mfa.Secret, _ = fields[0].(string)
+	mfa.Pending, _ = fields[1].(string)
+	mfa.RecoverySalt, _ = fields[3].(string)
+	if step, ok := fields[2].(string); ok {
+		if mfa.LastStep, err = strconv.ParseInt(step, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse totp step: %w", err)
+		}
+	}
+
+	return mfa, nil
+}
+
+// parseMFAChallenge will parse the fields of an mfa challenge hash
+func parseMFAChallenge(id string, fields map[string]string) (*repository.MFAChallenge, error) {
+	if len(fields) == 0 {
+		return nil, ErrNotExist
+	}
+
+	var err error
+	challenge := &repository.MFAChallenge{
+		Id:        id,
+		UserId:    fields["user_id"],
+		UserAgent: fields["user_agent"],
+		ClientIP:  fields["client_ip"],
+	}
+
+	if challenge.Attempts, err = strconv.Atoi(fields["attempts"]); err != nil {
+		return nil, fmt.Errorf("failed to parse mfa challenge attempts: %w", err)
+	}
+
+	unix, err := strconv.ParseInt(fields["expiration"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse mfa challenge expiration: %w", err)
+	}
+	challenge.Expiration = time.Unix(unix, 0)
+
+	if challenge.Expiration.Before(time.Now()) {
+		return nil, ErrNotExist
+	}
+
+	return challenge, nil
+}
+
+func (rks *redisKeyStore) GetMFAChallenge(id string) (*repository.MFAChallenge, error) {
+	fields, err := rks.client.HGetAll(rks.fmtMFAChallengeKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMFAChallenge(id, fields)
+}
+
+func (rks *redisKeyStore) TakeMFAChallenge(id string) (*repository.MFAChallenge, error) {
+	key := rks.fmtMFAChallengeKey(id)
+
+	var fieldsCmd *redis.StringStringMapCmd
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		fieldsCmd = pipe.HGetAll(key)
+		pipe.Del(key)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMFAChallenge(id, fieldsCmd.Val())
+}
+
+func (rks *redisKeyStore) SetPendingTOTP(userId, secret string) error {
+	return rks.client.HSet(rks.fmtUserId(userId), "totp_pending", secret).Err()
+}
+
+func (rks *redisKeyStore) EnableTOTP(userId, secret string) error {
+	userId = rks.fmtUserId(userId)
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HSet(userId, "totp", secret)
+		pipe.HDel(userId, "totp_pending")
+
+		return nil
+	})
+
+	return err
+}
+
+func (rks *redisKeyStore) RemoveTOTP(userId string) error {
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HDel(rks.fmtUserId(userId), "totp", "totp_pending", "totp_step", "recovery_salt")
+		pipe.Del(rks.fmtRecoveryKey(userId))
+
+		return nil
+	})
+
+	return err
+}
+
+func (rks *redisKeyStore) UseTOTPStep(userId string, step int64) (bool, error) {
+	used, err := useTOTPStep.Run(rks.client, []string{rks.fmtUserId(userId)}, step).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return used == 1, nil
+}
+
+func (rks *redisKeyStore) SetRecoveryCodes(userId, salt string, hashes []string) error {
+	key := rks.fmtRecoveryKey(userId)
+	members := make([]interface{}, len(hashes))
+	for i, hash := range hashes {
+		members[i] = hash
+	}
+
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(key)
+		if len(members) > 0 {
+			pipe.SAdd(key, members...)
+		}
+		pipe.HSet(rks.fmtUserId(userId), "recovery_salt", salt)
+
+		return nil
+	})
+
+	return err
+}
+
+func (rks *redisKeyStore) UseRecoveryCode(userId, hash string) (bool, error) {
+	removed, err := rks.client.SRem(rks.fmtRecoveryKey(userId), hash).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return removed == 1, nil
+}
+
+func (rks *redisKeyStore) SetMFAChallenge(challenge *repository.MFAChallenge) error {
+	key := rks.fmtMFAChallengeKey(challenge.Id)
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HMSet(key, map[string]interface{}{
+			"user_id":    challenge.UserId,
+			"user_agent": challenge.UserAgent,
+			"client_ip":  challenge.ClientIP,
+			"attempts":   challenge.Attempts,
+			"expiration": challenge.Expiration.Unix(),
+		})
+		pipe.ExpireAt(key, challenge.Expiration)
+
+		return nil
+	})
+
+	return err
+}
+
+func (rks *redisKeyStore) IncrMFAChallenge(id string) (int, error) {
+	attempts, err := incrMFAChallenge.Run(rks.client,
+		[]string{rks.fmtMFAChallengeKey(id)}).Int()
+	if err != nil {
+		return 0, err
+	}
+
+	if attempts < 0 {
+		return 0, ErrNotExist
+	}
+
+	return attempts, nil
+}
+
+func (rks *redisKeyStore) RemoveMFAChallenge(id string) error {
+	return rks.client.Del(rks.fmtMFAChallengeKey(id)).Err()
+}
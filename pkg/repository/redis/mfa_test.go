@@ -0,0 +1,173 @@
+package redis_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/repository"
+	"github.com/joshturge-io/auth/pkg/repository/redis"
+)
+
+func TestTOTP(t *testing.T) {
+	userId := "test_user_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := repo.SetPendingTOTP(userId, "pending"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	mfa, err := repo.GetMFA(userId)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if mfa.Pending != "pending" || mfa.Secret != "" || mfa.LastStep != -1 {
+		t.Errorf("unexpected mfa state after setting pending secret: %+v", mfa)
+	}
+
+	if err = repo.EnableTOTP(userId, "pending"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	for _, step := range []int64{10, 11} {
+		used, err := repo.UseTOTPStep(userId, step)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if !used {
+			t.Errorf("step %d should have been accepted", step)
+		}
+	}
+
+	// replayed and older steps are rejected
+	for _, step := range []int64{11, 10} {
+		used, err := repo.UseTOTPStep(userId, step)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if used {
+			t.Errorf("step %d should have been rejected", step)
+		}
+	}
+
+	if mfa, err = repo.GetMFA(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if mfa.Secret != "pending" || mfa.Pending != "" || mfa.LastStep != 11 {
+		t.Errorf("unexpected mfa state after enabling totp: %+v", mfa)
+	}
+
+	if err = repo.SetRecoveryCodes(userId, "salt", []string{"hash1", "hash2"}); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	for _, want := range []bool{true, false} {
+		used, err := repo.UseRecoveryCode(userId, "hash1")
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if used != want {
+			t.Errorf("recovery code use does not match wanted: %t got: %t", want, used)
+		}
+	}
+
+	if mfa, err = repo.GetMFA(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if mfa.RecoverySalt != "salt" || mfa.RecoveryCodes != 1 {
+		t.Errorf("unexpected recovery codes: %+v", mfa)
+	}
+
+	if err = repo.RemoveTOTP(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if mfa, err = repo.GetMFA(userId); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if mfa.Secret != "" || mfa.LastStep != -1 || mfa.RecoveryCodes != 0 {
+		t.Errorf("totp was not removed: %+v", mfa)
+	}
+}
+
+func TestMFAChallenge(t *testing.T) {
+	challenge := &repository.MFAChallenge{
+		Id:         "test_challenge_" + strconv.FormatInt(time.Now().UnixNano(), 10),
+		UserId:     "test_user",
+		UserAgent:  "grpc-go/1.28.0",
+		ClientIP:   "127.0.0.1",
+		Expiration: time.Now().Add(time.Minute),
+	}
+
+	if err := repo.SetMFAChallenge(challenge); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	for want := 1; want <= 2; want++ {
+		attempts, err := repo.IncrMFAChallenge(challenge.Id)
+		if err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+
+		if attempts != want {
+			t.Errorf("attempts do not match wanted: %d got: %d", want, attempts)
+		}
+	}
+
+	got, err := repo.GetMFAChallenge(challenge.Id)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if got.UserId != challenge.UserId || got.UserAgent != challenge.UserAgent ||
+		got.ClientIP != challenge.ClientIP || got.Attempts != 2 {
+		t.Errorf("challenge does not match the one set: %+v", got)
+	}
+
+	if err = repo.RemoveMFAChallenge(challenge.Id); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if _, err = repo.GetMFAChallenge(challenge.Id); !errors.Is(err, redis.ErrNotExist) {
+		t.Errorf("expected ErrNotExist got: %v", err)
+	}
+
+	if _, err = repo.IncrMFAChallenge(challenge.Id); !errors.Is(err, redis.ErrNotExist) {
+		t.Errorf("expected ErrNotExist got: %v", err)
+	}
+
+	if err = repo.SetMFAChallenge(challenge); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if got, err = repo.TakeMFAChallenge(challenge.Id); err != nil ||
+		got.UserId != challenge.UserId {
+		t.Errorf("expected to take the challenge got: %+v: %v", got, err)
+	}
+
+	if _, err = repo.TakeMFAChallenge(challenge.Id); !errors.Is(err, redis.ErrNotExist) {
+		t.Errorf("expected a challenge to only be taken once got: %v", err)
+	}
+}
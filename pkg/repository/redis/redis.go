@@ -3,7 +3,10 @@ package redis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,10 +18,37 @@ import (
 )
 
 var (
-	ErrNotExist     = errors.New("member does not exist")
+	ErrNotExist     = repository.ErrNotExist
 	ErrTokenExpired = errors.New("token has expired")
 )
 
+// createUser will set the salt and hash of a user only if the user doesn't already exist
+var createUser = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+redis.call("HMSET", KEYS[1], "salt", ARGV[1], "hash", ARGV[2])
+return 1
+`)
+
+// rotateSession will replace the fields of a session only if the current refresh token is the
+// parent of the new one, the parent is then added to the sessions rotated refresh tokens
+var rotateSession = redis.NewScript(`
+local current = redis.call("HGET", KEYS[1], "refresh")
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call("HMSET", KEYS[1], unpack(ARGV, 3))
+redis.call("HDEL", KEYS[1], "jwt")
+redis.call("SADD", KEYS[2], ARGV[1])
+redis.call("EXPIREAT", KEYS[1], ARGV[2])
+redis.call("EXPIREAT", KEYS[2], ARGV[2])
+return 1
+`)
+
 // redisKeyStore satisfies the Repository interface
 type redisKeyStore struct {
 	client   *redis.Client
@@ -57,34 +87,156 @@ func (rks *redisKeyStore) fmtUserId(userId string) string {
 	return strings.Join([]string{"user", userId}, ":")
 }
 
-func (rks *redisKeyStore) GetRefreshToken(userId string) (token string, err error) {
-	userId = rks.fmtUserId(userId)
+// fmtSessionKey will format the key of a single session of a user
+func (rks *redisKeyStore) fmtSessionKey(userId, sessionId string) string {
+	return strings.Join([]string{"session", strings.TrimPrefix(userId, "user:"), sessionId},
+		":")
+}
+
+// fmtSessionsKey will format the key of the set holding all session ids of a user
+func (rks *redisKeyStore) fmtSessionsKey(userId string) string {
+	return strings.Join([]string{"sessions", strings.TrimPrefix(userId, "user:")}, ":")
+}
+
+// fmtRolesKey will format the key of the set holding the roles of a user
+func (rks *redisKeyStore) fmtRolesKey(userId string) string {
+	return strings.Join([]string{"roles", strings.TrimPrefix(userId, "user:")}, ":")
+}
+
+// fmtRotatedKey will format the key of the set holding the rotated refresh tokens of a session
+func (rks *redisKeyStore) fmtRotatedKey(userId, sessionId string) string {
+	return strings.Join([]string{rks.fmtSessionKey(userId, sessionId), "rotated"}, ":")
+}
+
+// sessionFields will get the fields of a session hash
+func sessionFields(sess *repository.Session) map[string]interface{} {
+	return map[string]interface{}{
+		"refresh":    sess.Refresh,
+		"jwt_id":     sess.JWTId,
+		"jwt_exp":    sess.JWTExpiration.Unix(),
+		"parent":     sess.Parent,
+		"expiration": sess.Expiration.Unix(),
+		"created":    sess.CreatedAt.Unix(),
+		"last_used":  sess.LastUsed.Unix(),
+		"user_agent": sess.UserAgent,
+		"client_ip":  sess.ClientIP,
+	}
+}
+
+// parseSession will parse the fields of a session hash
+func parseSession(userId, sessionId string, fields map[string]string) (*repository.Session,
+	error) {
+	if len(fields) == 0 {
+		return nil, ErrNotExist
+	}
 
-	if rks.client.HExists(userId, "refresh").Val() {
-		exp, err := rks.client.HGet(userId, "expiration").Int64()
+	sess := &repository.Session{
+		Id:        sessionId,
+		UserId:    strings.TrimPrefix(userId, "user:"),
+		Refresh:   fields["refresh"],
+		JWTId:     fields["jwt_id"],
+		Parent:    fields["parent"],
+		UserAgent: fields["user_agent"],
+		ClientIP:  fields["client_ip"],
+	}
+
+	for field, t := range map[string]*time.Time{
+		"expiration": &sess.Expiration,
+		"created":    &sess.CreatedAt,
+		"last_used":  &sess.LastUsed,
+	} {
+		unix, err := strconv.ParseInt(fields[field], 10, 64)
 		if err != nil {
-			return token, err
+			return nil, fmt.Errorf("failed to parse session %s: %w", field, err)
 		}
+		*t = time.Unix(unix, 0)
+	}
+
+	// sessions stored before the jti was kept don't have a jwt expiration
+	if exp, ok := fields["jwt_exp"]; ok {
+		unix, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse session jwt_exp: %w", err)
+		}
+		sess.JWTExpiration = time.Unix(unix, 0)
+	}
+
+	if sess.Expiration.Before(time.Now()) {
+		return nil, ErrTokenExpired
+	}
+
+	return sess, nil
+}
+
+func (rks *redisKeyStore) GetSession(userId, sessionId string) (*repository.Session, error) {
+	fields, err := rks.client.HGetAll(rks.fmtSessionKey(userId, sessionId)).Result()
+	if err != nil {
+		return nil, err
+	}
 
-		if exp < time.Now().Unix() {
-			if err = rks.RemoveRefreshToken(userId); err != nil {
-				return token, err
+	sess, err := parseSession(userId, sessionId, fields)
+	if err != nil {
+		if errors.Is(err, ErrTokenExpired) {
+			if err = rks.RemoveSession(userId, sessionId); err != nil {
+				return nil, err
 			}
-			return token, ErrTokenExpired
+			return nil, ErrTokenExpired
+		}
+		return nil, err
+	}
+
+	return sess, nil
+}
+
+func (rks *redisKeyStore) GetSessions(userId string) ([]*repository.Session, error) {
+	ids, err := rks.client.SMembers(rks.fmtSessionsKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	if _, err = rks.client.Pipelined(func(pipe redis.Pipeliner) error {
+		for i, id := range ids {
+			cmds[i] = pipe.HGetAll(rks.fmtSessionKey(userId, id))
 		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
 
-		token, err = rks.client.HGet(userId, "refresh").Result()
+	var (
+		sessions = make([]*repository.Session, 0, len(ids))
+		stale    = []interface{}{}
+	)
+	for i, cmd := range cmds {
+		sess, err := parseSession(userId, ids[i], cmd.Val())
 		if err != nil {
-			if errors.Is(err, redis.Nil) {
-				return token, ErrNotExist
+			if errors.Is(err, ErrNotExist) || errors.Is(err, ErrTokenExpired) {
+				stale = append(stale, ids[i])
+				continue
 			}
-			return token, err
+			return nil, err
 		}
 
-		return token, err
+		sessions = append(sessions, sess)
+	}
+
+	// session hashes expire by themselves so clean up the ids left behind
+	if len(stale) > 0 {
+		if err = rks.client.SRem(rks.fmtSessionsKey(userId), stale...).Err(); err != nil {
+			return nil, err
+		}
 	}
 
-	return token, ErrNotExist
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (rks *redisKeyStore) IsRotatedRefresh(userId, sessionId, refresh string) (bool, error) {
+	return rks.client.SIsMember(rks.fmtRotatedKey(userId, sessionId), refresh).Result()
 }
 
 func (rks *redisKeyStore) GetSalt(userId string) (string, error) {
@@ -115,18 +267,55 @@ func (rks *redisKeyStore) GetHash(userId string) (string, error) {
 	return hash, nil
 }
 
-func (rks *redisKeyStore) SetRefreshToken(userId, token string,
-	exp time.Duration) (err error) {
+func (rks *redisKeyStore) GetRevocation(userId string) (time.Time, error) {
 	userId = rks.fmtUserId(userId)
 
-	_, err = rks.client.Pipelined(func(pipe redis.Pipeliner) error {
-		if err = pipe.HSet(userId, "expiration",
-			time.Now().Add(exp).Unix()).Err(); err != nil {
-			return err
-		}
-		if err = pipe.HSet(userId, "refresh", token).Err(); err != nil {
-			return err
+	revoked, err := rks.client.HGet(userId, "revoked").Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return time.Time{}, nil
 		}
+		return time.Time{}, err
+	}
+
+	return time.Unix(revoked, 0), nil
+}
+
+func (rks *redisKeyStore) GetRoles(userId string) ([]string, error) {
+	roles, err := rks.client.SMembers(rks.fmtRolesKey(userId)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(roles)
+
+	return roles, nil
+}
+
+func (rks *redisKeyStore) CreateUser(userId, salt, hash string) error {
+	userId = rks.fmtUserId(userId)
+
+	created, err := createUser.Run(rks.client, []string{userId}, salt, hash).Int()
+	if err != nil {
+		return err
+	}
+
+	if created == 0 {
+		return repository.ErrUserExist
+	}
+
+	return nil
+}
+
+func (rks *redisKeyStore) SetSession(sess *repository.Session) error {
+	key := rks.fmtSessionKey(sess.UserId, sess.Id)
+
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.HMSet(key, sessionFields(sess))
+		// sessions stored the whole jwt before only its jti was kept
+		pipe.HDel(key, "jwt")
+		pipe.ExpireAt(key, sess.Expiration)
+		pipe.SAdd(rks.fmtSessionsKey(sess.UserId), sess.Id)
 
 		return nil
 	})
@@ -134,6 +323,30 @@ func (rks *redisKeyStore) SetRefreshToken(userId, token string,
 	return err
 }
 
+func (rks *redisKeyStore) RotateSession(sess *repository.Session) error {
+	args := []interface{}{sess.Parent, sess.Expiration.Unix()}
+	for field, value := range sessionFields(sess) {
+		args = append(args, field, value)
+	}
+
+	rotated, err := rotateSession.Run(rks.client, []string{
+		rks.fmtSessionKey(sess.UserId, sess.Id),
+		rks.fmtRotatedKey(sess.UserId, sess.Id),
+	}, args...).Int()
+	if err != nil {
+		return err
+	}
+
+	switch rotated {
+	case -1:
+		return ErrNotExist
+	case 0:
+		return repository.ErrStaleRefresh
+	}
+
+	return nil
+}
+
 func (rks *redisKeyStore) SetSalt(userId, salt string) error {
 	userId = rks.fmtUserId(userId)
 	return rks.client.HSet(userId, "salt", salt).Err()
@@ -144,8 +357,30 @@ func (rks *redisKeyStore) SetHash(userId, hash string) error {
 	return rks.client.HSet(userId, "hash", hash).Err()
 }
 
-func (rks *redisKeyStore) IsBlacklisted(token string) (bool, error) {
-	_, err := rks.client.ZRank("blacklist", token).Result()
+func (rks *redisKeyStore) SetChallenge(userId, salt, hash string) error {
+	userId = rks.fmtUserId(userId)
+	return rks.client.HMSet(userId, map[string]interface{}{
+		"salt": salt,
+		"hash": hash,
+	}).Err()
+}
+
+func (rks *redisKeyStore) SetRevocation(userId string, before time.Time) error {
+	userId = rks.fmtUserId(userId)
+	return rks.client.HSet(userId, "revoked", before.Unix()).Err()
+}
+
+func (rks *redisKeyStore) AddRole(userId, role string) error {
+	return rks.client.SAdd(rks.fmtRolesKey(userId), role).Err()
+}
+
+func (rks *redisKeyStore) RemoveRole(userId, role string) error {
+	return rks.client.SRem(rks.fmtRolesKey(userId), role).Err()
+}
+
+// IsBlacklisted will check if a jwt id is a member of the blacklist
+func (rks *redisKeyStore) IsBlacklisted(tokenId string) (bool, error) {
+	_, err := rks.client.ZRank("blacklist", tokenId).Result()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return false, nil
@@ -156,20 +391,55 @@ func (rks *redisKeyStore) IsBlacklisted(token string) (bool, error) {
 	return true, nil
 }
 
-func (rks *redisKeyStore) SetBlacklist(token string, exp time.Duration) error {
+// SetBlacklist will add a jwt id to the blacklist scored by when the jwt expires so the
+// flusher can remove it once it has expired
+func (rks *redisKeyStore) SetBlacklist(tokenId string, exp time.Duration) error {
 	return rks.client.ZAdd("blacklist", redis.Z{
 		Score:  float64(time.Now().Add(exp).Unix()),
-		Member: token,
+		Member: tokenId,
 	}).Err()
 }
 
-func (rks *redisKeyStore) RemoveRefreshToken(userId string) error {
-	userId = rks.fmtUserId(userId)
-	return rks.client.HDel(userId, "refresh", "expiration").Err()
+func (rks *redisKeyStore) RemoveSession(userId, sessionId string) error {
+	_, err := rks.client.TxPipelined(func(pipe redis.Pipeliner) error {
+		pipe.Del(rks.fmtSessionKey(userId, sessionId), rks.fmtRotatedKey(userId, sessionId))
+		pipe.SRem(rks.fmtSessionsKey(userId), sessionId)
+
+		return nil
+	})
+
+	return err
+}
+
+func (rks *redisKeyStore) RemoveSessions(userId string) error {
+	ids, err := rks.client.SMembers(rks.fmtSessionsKey(userId)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)*2+1)
+	for _, id := range ids {
+		keys = append(keys, rks.fmtSessionKey(userId, id), rks.fmtRotatedKey(userId, id))
+	}
+	keys = append(keys, rks.fmtSessionsKey(userId))
+
+	return rks.client.Del(keys...).Err()
 }
 
-func (rks *redisKeyStore) WithContext(ctx context.Context) {
-	rks.WithContext(ctx)
+// WithContext does nothing, the client is shared by every request so it can't hold a request
+// context, and go-redis doesn't use the context for commands
+func (rks *redisKeyStore) WithContext(ctx context.Context) {}
+
+func (rks *redisKeyStore) Health() error {
+	if err := rks.client.Ping().Err(); err != nil {
+		return fmt.Errorf("unable to ping redis: %w", err)
+	}
+
+	if err := rks.flushSvc.Err(); err != nil {
+		return fmt.Errorf("failed to flush blacklist: %w", err)
+	}
+
+	return nil
 }
 
 func (rks *redisKeyStore) Close() error {
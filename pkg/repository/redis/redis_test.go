@@ -1,8 +1,10 @@
 package redis_test
 
 import (
+	"errors"
 	"log"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 var (
 	repo          repository.Repository
 	testUser      map[string]string
+	testSession   *repository.Session
 	testBlacklist []string
 )
 
@@ -26,28 +29,79 @@ func init() {
 	}
 
 	testUser = map[string]string{
-		"salt":    "H4jk53hGsk3fj4Dfsj3",
-		"hash":    "dd373f6f7e9338d82a5ccab1be65475c06e97fed63cd59b892024a0a120aa6f0",
-		"refresh": "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
+		"salt": "H4jk53hGsk3fj4Dfsj3",
+		"hash": "dd373f6f7e9338d82a5ccab1be65475c06e97fed63cd59b892024a0a120aa6f0",
+	}
+
+	testSession = &repository.Session{
+		Id:         "test_session",
+		UserId:     "test_user",
+		Refresh:    "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
+		Expiration: time.Now().Add(3 * time.Minute),
+		CreatedAt:  time.Now(),
+		LastUsed:   time.Now(),
+		UserAgent:  "grpc-go/1.28.0",
+		ClientIP:   "127.0.0.1",
+
+		JWTId:         "9f86d081884c7d659a2feaa0c55ad015",
+		JWTExpiration: time.Now().Add(time.Minute),
 	}
 
 	testBlacklist = []string{}
 }
 
-func TestSetRefreshToken(t *testing.T) {
-	if err := repo.SetRefreshToken("test_user", testUser["refresh"], 3*time.Minute); err != nil {
+func TestSetSession(t *testing.T) {
+	if err := repo.SetSession(testSession); err != nil {
 		t.Error(err)
 	}
 }
 
-func TestGetRefreshToken(t *testing.T) {
-	token, err := repo.GetRefreshToken("test_user")
+func TestGetSession(t *testing.T) {
+	sess, err := repo.GetSession("test_user", testSession.Id)
 	if err != nil {
 		t.Error(err)
+		t.FailNow()
+	}
+
+	if sess.Refresh != testSession.Refresh {
+		t.Errorf("token does not match the one set wanted: %s got: %s\n", testSession.Refresh,
+			sess.Refresh)
+	}
+
+	if sess.UserAgent != testSession.UserAgent || sess.ClientIP != testSession.ClientIP {
+		t.Errorf("device does not match the one set wanted: %s %s got: %s %s",
+			testSession.UserAgent, testSession.ClientIP, sess.UserAgent, sess.ClientIP)
 	}
 
-	if token != testUser["refresh"] {
-		t.Errorf("token does not match the one set wanted: %s got: %s\n", testUser["refresh"], token)
+	if sess.JWTId != testSession.JWTId ||
+		sess.JWTExpiration.Unix() != testSession.JWTExpiration.Unix() {
+		t.Errorf("jwt does not match the one set wanted: %s %s got: %s %s", testSession.JWTId,
+			testSession.JWTExpiration, sess.JWTId, sess.JWTExpiration)
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	other := *testSession
+	other.Id = "other_session"
+	if err := repo.SetSession(&other); err != nil {
+		t.Error(err)
+	}
+
+	sessions, err := repo.GetSessions("test_user")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if len(sessions) != 2 {
+		t.Errorf("expected 2 sessions got: %d", len(sessions))
+	}
+
+	if err = repo.RemoveSession("test_user", other.Id); err != nil {
+		t.Error(err)
+	}
+
+	if _, err = repo.GetSession("test_user", other.Id); !errors.Is(err, redis.ErrNotExist) {
+		t.Errorf("expected ErrNotExist for removed session got: %v", err)
 	}
 }
 
@@ -86,14 +140,14 @@ func TestGetHash(t *testing.T) {
 }
 
 func TestSetBlacklist(t *testing.T) {
-	jw := token.NewJW("secret", "test_user", 3*time.Minute)
+	jw := token.NewJW(token.NewHMACKey("secret"), nil, "test_user", 3*time.Minute)
 	if err := jw.Generate(); err != nil {
 		t.Error(err)
 	}
 
-	testBlacklist = append(testBlacklist, jw.Token())
+	testBlacklist = append(testBlacklist, jw.Id())
 
-	if err := repo.SetBlacklist(jw.Token(), 3*time.Minute); err != nil {
+	if err := repo.SetBlacklist(jw.Id(), 3*time.Minute); err != nil {
 		t.Error(err)
 	}
 }
@@ -108,3 +162,135 @@ func TestIsBlacklisted(t *testing.T) {
 		t.Error("token was not blacklisted")
 	}
 }
+
+func TestCreateUser(t *testing.T) {
+	userId := "test_user_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	if err := repo.CreateUser(userId, testUser["salt"], testUser["hash"]); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if err := repo.CreateUser(userId, testUser["salt"],
+		testUser["hash"]); !errors.Is(err, repository.ErrUserExist) {
+		t.Errorf("expected ErrUserExist got: %v", err)
+	}
+
+	hash, err := repo.GetHash(userId)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if hash != testUser["hash"] {
+		t.Errorf("hash does not match the one set wanted: %s got: %s", testUser["hash"], hash)
+	}
+}
+
+func TestSetChallenge(t *testing.T) {
+	if err := repo.SetChallenge("test_user", testUser["salt"], testUser["hash"]); err != nil {
+		t.Error(err)
+	}
+
+	salt, err := repo.GetSalt("test_user")
+	if err != nil {
+		t.Error(err)
+	}
+
+	hash, err := repo.GetHash("test_user")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if salt != testUser["salt"] || hash != testUser["hash"] {
+		t.Errorf("challenge does not match the one set wanted: %s %s got: %s %s",
+			testUser["salt"], testUser["hash"], salt, hash)
+	}
+}
+
+func TestSetRevocation(t *testing.T) {
+	before := time.Now()
+	if err := repo.SetRevocation("test_user", before); err != nil {
+		t.Error(err)
+	}
+
+	revoked, err := repo.GetRevocation("test_user")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if revoked.Unix() != before.Unix() {
+		t.Errorf("revocation does not match the one set wanted: %d got: %d", before.Unix(),
+			revoked.Unix())
+	}
+}
+
+func TestRotateSession(t *testing.T) {
+	rotated := *testSession
+	rotated.Id = "rotated_session"
+	if err := repo.SetSession(&rotated); err != nil {
+		t.Error(err)
+	}
+
+	rotated.Parent = rotated.Refresh
+	rotated.Refresh = "gPx8T2hQ6mvyLq3o_d0k1fJ5pZ0bQ2yN3cO7xR4sW9E="
+	if err := repo.RotateSession(&rotated); err != nil {
+		t.Error(err)
+	}
+
+	if err := repo.RotateSession(&rotated); !errors.Is(err, repository.ErrStaleRefresh) {
+		t.Errorf("expected ErrStaleRefresh got: %v", err)
+	}
+
+	reused, err := repo.IsRotatedRefresh("test_user", rotated.Id, rotated.Parent)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reused {
+		t.Error("parent refresh token was not stored as rotated")
+	}
+
+	if err = repo.RemoveSession("test_user", rotated.Id); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	userId := "test_user_" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	for _, role := range []string{"user", "admin", "user"} {
+		if err := repo.AddRole(userId, role); err != nil {
+			t.Error(err)
+			t.FailNow()
+		}
+	}
+
+	roles, err := repo.GetRoles(userId)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(roles) != 2 || roles[0] != "admin" || roles[1] != "user" {
+		t.Errorf("roles do not match wanted: [admin user] got: %v", roles)
+	}
+
+	if err = repo.RemoveRole(userId, "admin"); err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	roles, err = repo.GetRoles(userId)
+	if err != nil {
+		t.Error(err)
+		t.FailNow()
+	}
+
+	if len(roles) != 1 || roles[0] != "user" {
+		t.Errorf("roles do not match wanted: [user] got: %v", roles)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	if err := repo.Health(); err != nil {
+		t.Errorf("expected repository to be healthy got: %v", err)
+	}
+}
@@ -2,32 +2,162 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"io"
 	"time"
 )
 
+var (
+	ErrNotExist     = errors.New("member does not exist")
+	ErrUserExist    = errors.New("user already exists")
+	ErrStaleRefresh = errors.New("refresh token has already been rotated")
+)
+
+// Session is the stored record of one of a users sessions
+type Session struct {
+	Id         string
+	UserId     string
+	Refresh    string
+	Expiration time.Time
+	CreatedAt  time.Time
+	LastUsed   time.Time
+	UserAgent  string
+	ClientIP   string
+	// the jti and expiration of the most recent jwt issued to the session, the jwt itself
+	// isn't stored
+	JWTId         string
+	JWTExpiration time.Time
+	// the refresh token the current refresh token was rotated from
+	Parent string
+}
+
+// MFA is the stored multi-factor authentication state of a user
+type MFA struct {
+	// encrypted totp secret, empty when totp isn't enabled
+	Secret string
+	// encrypted totp secret waiting to be confirmed
+	Pending string
+	// the last totp time step that was accepted
+	LastStep int64
+	// hex encoded salt the recovery codes of the user are hashed with
+	RecoverySalt string
+	// number of unused recovery codes
+	RecoveryCodes int
+}
+
+// MFAChallenge is issued to a user that passed their password challenge but still needs to
+// provide a second factor
+type MFAChallenge struct {
+	Id         string
+	UserId     string
+	UserAgent  string
+	ClientIP   string
+	Attempts   int
+	Expiration time.Time
+}
+
+// Failures are the failed attempts of a user or client within a sliding window
+type Failures struct {
+	Count int
+	// time of the latest failed attempt, zero when there are none
+	Last time.Time
+}
+
 type Withdrawer interface {
-	GetRefreshToken(userId string) (string, error)
+	// GetSession will get a single session of a user, returns ErrNotExist if the session
+	// doesn't exist
+	GetSession(userId, sessionId string) (*Session, error)
+	// GetSessions will get every active session of a user ordered by creation time
+	GetSessions(userId string) ([]*Session, error)
+	// IsRotatedRefresh will check if a refresh token was once used by a session but has since
+	// been rotated
+	IsRotatedRefresh(userId, sessionId, refresh string) (bool, error)
 	GetSalt(userId string) (string, error)
 	GetHash(userId string) (string, error)
+	// GetRevocation will get the time before which a users tokens are revoked, returns
+	// the zero time if the users tokens have never been revoked
+	GetRevocation(userId string) (time.Time, error)
+	// GetRoles will get the names of every role assigned to a user
+	GetRoles(userId string) ([]string, error)
+	GetMFA(userId string) (*MFA, error)
+	// GetMFAChallenge will get an mfa challenge by id, returns ErrNotExist if the challenge
+	// doesn't exist or has expired
+	GetMFAChallenge(id string) (*MFAChallenge, error)
+	// GetFailures will get the failed attempts of a subject within a window ending now
+	GetFailures(subject string, window time.Duration) (*Failures, error)
+	// GetLockout will get the time a users account is locked until, returns the zero time if
+	// the account isn't locked
+	GetLockout(userId string) (time.Time, error)
 }
 
 type Depositor interface {
-	SetRefreshToken(userId string, token string, exp time.Duration) error
+	// CreateUser will atomically create a user with a salt and hash, returns ErrUserExist if
+	// the user already exists
+	CreateUser(userId, salt, hash string) error
+	// SetSession will create or replace a session, the session will expire at its expiration
+	SetSession(sess *Session) error
+	// RotateSession will replace a session only if its current refresh token is the parent of
+	// the new session. The parent is kept as a rotated refresh token of the session, returns
+	// ErrStaleRefresh if the parent is no longer the current refresh token
+	RotateSession(sess *Session) error
 	SetSalt(userId string, salt string) error
 	SetHash(userId string, hash string) error
+	// SetChallenge will set both the salt and hash of a user atomically
+	SetChallenge(userId, salt, hash string) error
+	// SetRevocation will revoke every token issued to a user before a time, the time is kept
+	// with second precision
+	SetRevocation(userId string, before time.Time) error
+	AddRole(userId, role string) error
+	RemoveRole(userId, role string) error
+	SetPendingTOTP(userId, secret string) error
+	// EnableTOTP will set the totp secret of a user and remove the pending secret
+	EnableTOTP(userId, secret string) error
+	// RemoveTOTP will remove the totp secret, pending secret, last step and recovery codes of
+	// a user
+	RemoveTOTP(userId string) error
+	// UseTOTPStep will set the last accepted totp time step of a user only if it is after the
+	// current one, returns false if the step has already been used
+	UseTOTPStep(userId string, step int64) (bool, error)
+	// SetRecoveryCodes will replace the recovery code hashes of a user along with the salt
+	// they were hashed with
+	SetRecoveryCodes(userId, salt string, hashes []string) error
+	// UseRecoveryCode will atomically remove a recovery code hash of a user, returns false if
+	// the hash doesn't exist or has already been used
+	UseRecoveryCode(userId, hash string) (bool, error)
+	// SetMFAChallenge will store an mfa challenge until it expires
+	SetMFAChallenge(challenge *MFAChallenge) error
+	// IncrMFAChallenge will increment the failed attempts of an mfa challenge, returns the
+	// number of attempts
+	IncrMFAChallenge(id string) (int, error)
+	// TakeMFAChallenge will get and remove an mfa challenge in one step so it can only be
+	// taken once, returns ErrNotExist if the challenge doesn't exist or has expired
+	TakeMFAChallenge(id string) (*MFAChallenge, error)
+	RemoveMFAChallenge(id string) error
+	// AddFailure will record a failed attempt of a subject and forget attempts older than the
+	// window, returns the failed attempts left within the window
+	AddFailure(subject string, at time.Time, window time.Duration) (*Failures, error)
+	ClearFailures(subject string) error
+	// SetLockout will lock a users account until a time
+	SetLockout(userId string, until time.Time) error
+	RemoveLockout(userId string) error
 }
 
 type DepositWithdrawer interface {
 	Withdrawer
 	Depositor
-	SetBlacklist(token string, exp time.Duration) error
-	IsBlacklisted(token string) (bool, error)
-	RemoveRefreshToken(userId string) error
+	// SetBlacklist will blacklist the id of a jwt until it expires
+	SetBlacklist(tokenId string, exp time.Duration) error
+	IsBlacklisted(tokenId string) (bool, error)
+	RemoveSession(userId, sessionId string) error
+	// RemoveSessions will remove every session of a user
+	RemoveSessions(userId string) error
 	WithContext(ctx context.Context)
 }
 
 type Repository interface {
 	io.Closer
 	DepositWithdrawer
+	// Health will check the repository can be reached and its background services are
+	// working, returns nil when healthy
+	Health() error
 }
@@ -4,74 +4,496 @@ import (
 	"context"
 	"sort"
 	"strconv"
+	"sync"
 	"time"
 )
 
+// TestUser is the record used for any user id that hasn't been created through CreateUser
 var TestUser = map[string]string{
-	"salt":       "25b072f201ef24e750dcc558eaf2d8f3",
-	"hash":       "1743545c93d519060a72e5671a66cbe898163b41d8be2a92a57ac3b6a2650c8394cf4f009aa0df642721145694879ace89c1a9973ff601538220d6a59f665524022fc789a3f6512d7f4654ff8f39c7ba7ec5b12e93c08df97be9f8a4",
-	"refresh":    "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
-	"expiration": strconv.FormatInt(time.Now().Add(24*time.Hour).Unix(), 10),
+	"salt": "25b072f201ef24e750dcc558eaf2d8f3",
+	"hash": "1743545c93d519060a72e5671a66cbe898163b41d8be2a92a57ac3b6a2650c8394cf4f009aa0df642721145694879ace89c1a9973ff601538220d6a59f665524022fc789a3f6512d7f4654ff8f39c7ba7ec5b12e93c08df97be9f8a4",
 }
 
+// TestUsers holds the records of users created through CreateUser
+var TestUsers = map[string]map[string]string{}
+
+// TestSessions holds every session keyed by session id
+var TestSessions = map[string]*Session{
+	"session": {
+		Id:         "session",
+		UserId:     "user",
+		Refresh:    "Uq_XJB5p5clZ_lAjFVND0oTYT9uFe8plBfGHFGMZ4RI=",
+		Expiration: time.Now().Add(24 * time.Hour),
+		CreatedAt:  time.Now(),
+		LastUsed:   time.Now(),
+	},
+}
+
+// TestRotated holds the rotated refresh tokens of each session keyed by session id
+var TestRotated = map[string][]string{}
+
 var TestBlacklist = []string{}
 
-type testRepository struct{}
+// TestRoles holds the roles of each user keyed by user id
+var TestRoles = map[string][]string{}
+
+// TestMFA holds the mfa state of each user keyed by user id
+var TestMFA = map[string]*MFA{}
+
+// TestRecoveryCodes holds the recovery code hashes of each user keyed by user id
+var TestRecoveryCodes = map[string][]string{}
+
+// TestFailures holds the times of failed attempts keyed by subject
+var TestFailures = map[string][]time.Time{}
+
+// TestLockouts holds the time each locked account is locked until keyed by user id
+var TestLockouts = map[string]time.Time{}
+
+// TestMFAChallenges holds every mfa challenge keyed by id
+var TestMFAChallenges = map[string]*MFAChallenge{}
+
+type testRepository struct {
+	mu sync.Mutex
+}
 
 func NewTestRepository() Repository {
 	return &testRepository{}
 }
 
-func (tr *testRepository) GetRefreshToken(TestUserId string) (string, error) {
-	return TestUser["refresh"], nil
+// user will get the record for a user id, falling back to TestUser
+func (tr *testRepository) user(TestUserId string) map[string]string {
+	if user, ok := TestUsers[TestUserId]; ok {
+		return user
+	}
+	return TestUser
+}
+
+func (tr *testRepository) GetSession(TestUserId, sessionId string) (*Session, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	sess, ok := TestSessions[sessionId]
+	if !ok || sess.UserId != TestUserId || sess.Expiration.Before(time.Now()) {
+		return nil, ErrNotExist
+	}
+
+	copied := *sess
+
+	return &copied, nil
+}
+
+func (tr *testRepository) GetSessions(TestUserId string) ([]*Session, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	sessions := []*Session{}
+	for _, sess := range TestSessions {
+		if sess.UserId == TestUserId && sess.Expiration.After(time.Now()) {
+			copied := *sess
+			sessions = append(sessions, &copied)
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (tr *testRepository) IsRotatedRefresh(TestUserId, sessionId, refresh string) (bool,
+	error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for _, rotated := range TestRotated[sessionId] {
+		if rotated == refresh {
+			return true, nil
+		}
+	}
+
+	return false, nil
 }
 
 func (tr *testRepository) GetSalt(TestUserId string) (string, error) {
-	return TestUser["salt"], nil
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	salt, ok := tr.user(TestUserId)["salt"]
+	if !ok {
+		return "", ErrNotExist
+	}
+
+	return salt, nil
 }
 
 func (tr *testRepository) GetHash(TestUserId string) (string, error) {
-	return TestUser["hash"], nil
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return tr.user(TestUserId)["hash"], nil
 }
 
-func (tr *testRepository) SetRefreshToken(TestUserId, token string, exp time.Duration) error {
-	TestUser["refresh"] = token
-	TestUser["expiration"] = strconv.FormatInt(time.Now().Add(exp).Unix(), 10)
+func (tr *testRepository) GetRevocation(TestUserId string) (time.Time, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	revoked, ok := tr.user(TestUserId)["revoked"]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	unix, err := strconv.ParseInt(revoked, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+func (tr *testRepository) GetRoles(TestUserId string) ([]string, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	roles := append([]string{}, TestRoles[TestUserId]...)
+	sort.Strings(roles)
+	return roles, nil
+}
+
+// mfa will get the mfa state of a user, creating it if it doesn't exist
+func (tr *testRepository) mfa(TestUserId string) *MFA {
+	mfa, ok := TestMFA[TestUserId]
+	if !ok {
+		mfa = &MFA{LastStep: -1}
+		TestMFA[TestUserId] = mfa
+	}
+	return mfa
+}
+
+func (tr *testRepository) GetMFA(TestUserId string) (*MFA, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	copied := *tr.mfa(TestUserId)
+	copied.RecoveryCodes = len(TestRecoveryCodes[TestUserId])
+	return &copied, nil
+}
+
+func (tr *testRepository) GetMFAChallenge(id string) (*MFAChallenge, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	challenge, ok := TestMFAChallenges[id]
+	if !ok || challenge.Expiration.Before(time.Now()) {
+		return nil, ErrNotExist
+	}
+
+	copied := *challenge
+
+	return &copied, nil
+}
+
+func (tr *testRepository) GetFailures(subject string, window time.Duration) (*Failures, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	failures := &Failures{}
+	since := time.Now().Add(-window)
+	for _, at := range TestFailures[subject] {
+		if at.After(since) {
+			failures.Count++
+			if at.After(failures.Last) {
+				failures.Last = at
+			}
+		}
+	}
+	return failures, nil
+}
+
+func (tr *testRepository) GetLockout(TestUserId string) (time.Time, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	until := TestLockouts[TestUserId]
+	if until.Before(time.Now()) {
+		return time.Time{}, nil
+	}
+	return until, nil
+}
+
+func (tr *testRepository) CreateUser(TestUserId, salt, hash string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if _, ok := TestUsers[TestUserId]; ok {
+		return ErrUserExist
+	}
+
+	TestUsers[TestUserId] = map[string]string{
+		"salt": salt,
+		"hash": hash,
+	}
+
+	return nil
+}
+
+func (tr *testRepository) SetSession(sess *Session) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	copied := *sess
+	TestSessions[sess.Id] = &copied
+	return nil
+}
+
+func (tr *testRepository) RotateSession(sess *Session) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	current, ok := TestSessions[sess.Id]
+	if !ok || current.UserId != sess.UserId {
+		return ErrNotExist
+	}
+
+	if current.Refresh != sess.Parent {
+		return ErrStaleRefresh
+	}
+
+	copied := *sess
+	TestSessions[sess.Id] = &copied
+	TestRotated[sess.Id] = append(TestRotated[sess.Id], sess.Parent)
+
 	return nil
 }
 
 func (tr *testRepository) SetSalt(TestUserId, salt string) error {
-	TestUser["salt"] = salt
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.user(TestUserId)["salt"] = salt
 	return nil
 }
 
 func (tr *testRepository) SetHash(TestUserId, hash string) error {
-	TestUser["hash"] = hash
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.user(TestUserId)["hash"] = hash
+	return nil
+}
+
+func (tr *testRepository) SetChallenge(TestUserId, salt, hash string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	user := tr.user(TestUserId)
+	user["salt"] = salt
+	user["hash"] = hash
 	return nil
 }
 
-func (tr *testRepository) SetBlacklist(token string, exp time.Duration) error {
-	TestBlacklist = append(TestBlacklist, token)
+func (tr *testRepository) SetRevocation(TestUserId string, before time.Time) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.user(TestUserId)["revoked"] = strconv.FormatInt(before.Unix(), 10)
 	return nil
 }
 
-func (tr *testRepository) IsBlacklisted(token string) (bool, error) {
+func (tr *testRepository) AddRole(TestUserId, role string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for _, r := range TestRoles[TestUserId] {
+		if r == role {
+			return nil
+		}
+	}
+	TestRoles[TestUserId] = append(TestRoles[TestUserId], role)
+	return nil
+}
+
+func (tr *testRepository) RemoveRole(TestUserId, role string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	roles := []string{}
+	for _, r := range TestRoles[TestUserId] {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+	TestRoles[TestUserId] = roles
+	return nil
+}
+
+func (tr *testRepository) SetPendingTOTP(TestUserId, secret string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.mfa(TestUserId).Pending = secret
+	return nil
+}
+
+func (tr *testRepository) EnableTOTP(TestUserId, secret string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	mfa := tr.mfa(TestUserId)
+	mfa.Secret = secret
+	mfa.Pending = ""
+	return nil
+}
+
+func (tr *testRepository) RemoveTOTP(TestUserId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(TestMFA, TestUserId)
+	delete(TestRecoveryCodes, TestUserId)
+	return nil
+}
+
+func (tr *testRepository) UseTOTPStep(TestUserId string, step int64) (bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	mfa := tr.mfa(TestUserId)
+	if step <= mfa.LastStep {
+		return false, nil
+	}
+	mfa.LastStep = step
+	return true, nil
+}
+
+func (tr *testRepository) SetRecoveryCodes(TestUserId, salt string, hashes []string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.mfa(TestUserId).RecoverySalt = salt
+	TestRecoveryCodes[TestUserId] = append([]string{}, hashes...)
+	return nil
+}
+
+func (tr *testRepository) UseRecoveryCode(TestUserId, hash string) (bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	hashes := TestRecoveryCodes[TestUserId]
+	for i, h := range hashes {
+		if h == hash {
+			TestRecoveryCodes[TestUserId] = append(hashes[:i:i], hashes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (tr *testRepository) AddFailure(subject string, at time.Time,
+	window time.Duration) (*Failures, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	kept := []time.Time{at}
+	for _, failure := range TestFailures[subject] {
+		if failure.After(at.Add(-window)) {
+			kept = append(kept, failure)
+		}
+	}
+	TestFailures[subject] = kept
+	return &Failures{Count: len(kept), Last: at}, nil
+}
+
+func (tr *testRepository) ClearFailures(subject string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(TestFailures, subject)
+	return nil
+}
+
+func (tr *testRepository) SetLockout(TestUserId string, until time.Time) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	TestLockouts[TestUserId] = until
+	return nil
+}
+
+func (tr *testRepository) RemoveLockout(TestUserId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(TestLockouts, TestUserId)
+	return nil
+}
+
+func (tr *testRepository) SetMFAChallenge(challenge *MFAChallenge) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	copied := *challenge
+	TestMFAChallenges[challenge.Id] = &copied
+	return nil
+}
+
+func (tr *testRepository) IncrMFAChallenge(id string) (int, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	challenge, ok := TestMFAChallenges[id]
+	if !ok {
+		return 0, ErrNotExist
+	}
+	challenge.Attempts++
+	return challenge.Attempts, nil
+}
+
+func (tr *testRepository) TakeMFAChallenge(id string) (*MFAChallenge, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	challenge, ok := TestMFAChallenges[id]
+	if !ok || challenge.Expiration.Before(time.Now()) {
+		return nil, ErrNotExist
+	}
+
+	delete(TestMFAChallenges, id)
+
+	return challenge, nil
+}
+
+func (tr *testRepository) RemoveMFAChallenge(id string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	delete(TestMFAChallenges, id)
+	return nil
+}
+
+func (tr *testRepository) SetBlacklist(tokenId string, exp time.Duration) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	TestBlacklist = append(TestBlacklist, tokenId)
+	return nil
+}
+
+func (tr *testRepository) IsBlacklisted(tokenId string) (bool, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
 	sort.Strings(TestBlacklist)
-	index := sort.SearchStrings(TestBlacklist, token)
+	index := sort.SearchStrings(TestBlacklist, tokenId)
 
-	return index < len(TestBlacklist) && TestBlacklist[index] == token, nil
+	return index < len(TestBlacklist) && TestBlacklist[index] == tokenId, nil
 }
 
-func (tr *testRepository) RemoveRefreshToken(TestUserId string) error {
-	TestUser["refresh"] = ""
-	TestUser["expiration"] = ""
+func (tr *testRepository) RemoveSession(TestUserId, sessionId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if sess, ok := TestSessions[sessionId]; ok && sess.UserId == TestUserId {
+		delete(TestSessions, sessionId)
+		delete(TestRotated, sessionId)
+	}
+	return nil
+}
+
+func (tr *testRepository) RemoveSessions(TestUserId string) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for id, sess := range TestSessions {
+		if sess.UserId == TestUserId {
+			delete(TestSessions, id)
+			delete(TestRotated, id)
+		}
+	}
 	return nil
 }
 
 func (tr *testRepository) WithContext(ctx context.Context) {}
 
+func (tr *testRepository) Health() error {
+	return nil
+}
+
 func (tr *testRepository) Close() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
 	TestUser = nil
+	TestUsers = map[string]map[string]string{}
+	TestSessions = map[string]*Session{}
+	TestRotated = map[string][]string{}
 	TestBlacklist = []string{}
+	TestRoles = map[string][]string{}
+	TestMFA = map[string]*MFA{}
+	TestMFAChallenges = map[string]*MFAChallenge{}
+	TestRecoveryCodes = map[string][]string{}
+	TestFailures = map[string][]time.Time{}
+	TestLockouts = map[string]time.Time{}
 	return nil
 }
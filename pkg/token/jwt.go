@@ -1,6 +1,8 @@
 package token
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"time"
@@ -8,32 +10,94 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
-var ErrJWInvalid = errors.New("token is invalid")
+var (
+	ErrJWInvalid = errors.New("token is invalid")
+	ErrJWExpired = errors.New("token has expired")
+)
+
+// Options are the registered claims set on generated jwts and checked on parsed jwts
+type Options struct {
+	// Issuer is set as the iss claim, parsed jwts must have the same issuer when not empty
+	Issuer string
+	// Audience is set as the aud claim, parsed jwts must be intended for at least one of the
+	// audiences when not empty
+	Audience []string
+	// Leeway allowed for clock skew when checking exp, nbf and iat
+	Leeway time.Duration
+}
+
+// Grants are the roles and permissions of a user, embedded in jwts so resource servers can
+// make authorization decisions from the token alone
+type Grants struct {
+	Roles       []string
+	Permissions []string
+	// the highest permission level of the users roles
+	PermLevel int
+}
 
 type authClaims struct {
-	Username string `json:"username"`
-	jwt.StandardClaims
+	// kept alongside sub for consumers that read the username claim
+	Username  string      `json:"username"`
+	Subject   string      `json:"sub"`
+	Issuer    string      `json:"iss,omitempty"`
+	Audience  interface{} `json:"aud,omitempty"`
+	ExpiresAt int64       `json:"exp"`
+	IssuedAt  int64       `json:"iat"`
+	NotBefore int64       `json:"nbf"`
+	Id        string      `json:"jti"`
+
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	PermLevel   int      `json:"perm_level"`
+}
+
+// Valid is left to NewJWFromExisting so leeway can be applied
+func (c *authClaims) Valid() error {
+	return nil
 }
 
 type JW struct {
-	secret, username string
-	exp              time.Time
-	tokenStr         string
+	key           *Key
+	opt           *Options
+	username, id  string
+	issuer        string
+	audience      []string
+	grants        Grants
+	exp, iat, nbf time.Time
+	tokenStr      string
 }
 
-func NewJW(secret, username string, exp time.Duration) *JW {
-	return &JW{secret, username, time.Now().Add(exp), ""}
+// NewJW will create a jwt that gets signed with key when generated, opt can be nil
+func NewJW(key *Key, opt *Options, username string, exp time.Duration) *JW {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	now := time.Now()
+	return &JW{
+		key:      key,
+		opt:      opt,
+		username: username,
+		issuer:   opt.Issuer,
+		audience: opt.Audience,
+		exp:      now.Add(exp),
+		iat:      now,
+		nbf:      now,
+	}
 }
 
-func NewJWFromExisting(secret, tokenStr string) (*JW, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v: %w", token.Header["alg"], ErrJWInvalid)
-		}
-		return []byte(secret), nil
-	})
+// NewJWFromExisting will parse a jwt, verify it with the key found by the verifier and
+// validate its registered claims against opt, opt can be nil
+func NewJWFromExisting(v Verifier, opt *Options, tokenStr string) (*JW, error) {
+	if opt == nil {
+		opt = &Options{}
+	}
+
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(tokenStr, v.VerifyKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse token: %s: %w", tokenStr, err)
+		// the token is left out of the error so it can't leak into logs or responses
+		return nil, fmt.Errorf("failed to parse token: %s: %w", err, ErrJWInvalid)
 	}
 
 	if !token.Valid {
@@ -45,9 +109,13 @@ func NewJWFromExisting(secret, tokenStr string) (*JW, error) {
 		return nil, fmt.Errorf("failed to extract claims: token claims type: %T: %w", claims, ErrJWInvalid)
 	}
 
-	t := &JW{secret: secret, tokenStr: tokenStr}
+	t := &JW{opt: opt, tokenStr: tokenStr}
 
-	t.username, ok = claims["username"].(string)
+	// tokens issued before the sub claim was added only have a username
+	t.username, ok = claims["sub"].(string)
+	if !ok {
+		t.username, ok = claims["username"].(string)
+	}
 	if !ok {
 		return nil, fmt.Errorf("failed to extract username from claim: wanted: string got: %T: %w",
 			claims["username"], ErrJWInvalid)
@@ -61,22 +129,127 @@ func NewJWFromExisting(secret, tokenStr string) (*JW, error) {
 
 	t.exp = time.Unix(int64(exp), 0)
 
+	// tokens issued before the iat and nbf claims were added won't have them
+	if iat, ok := claims["iat"].(float64); ok {
+		t.iat = time.Unix(int64(iat), 0)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok {
+		t.nbf = time.Unix(int64(nbf), 0)
+	}
+
+	t.id, _ = claims["jti"].(string)
+	t.issuer, _ = claims["iss"].(string)
+
+	if aud, ok := claims["aud"].(string); ok {
+		t.audience = []string{aud}
+	} else {
+		t.audience = stringsClaim(claims["aud"])
+	}
+
+	t.grants.Roles = stringsClaim(claims["roles"])
+	t.grants.Permissions = stringsClaim(claims["permissions"])
+	if level, ok := claims["perm_level"].(float64); ok {
+		t.grants.PermLevel = int(level)
+	}
+
+	if err = t.validate(); err != nil {
+		return nil, err
+	}
+
 	return t, nil
 }
 
+// stringsClaim will get the strings of an array claim, returns nil if the claim isn't an array
+func stringsClaim(claim interface{}) []string {
+	values, ok := claim.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
+
+// validate will check the registered claims of a parsed jwt
+func (t *JW) validate() error {
+	if t.IsExpired() {
+		return ErrJWExpired
+	}
+
+	now := time.Now()
+
+	if now.Add(t.opt.Leeway).Before(t.nbf) {
+		return fmt.Errorf("token is not valid before: %s: %w", t.nbf, ErrJWInvalid)
+	}
+
+	if now.Add(t.opt.Leeway).Before(t.iat) {
+		return fmt.Errorf("token was issued in the future: %s: %w", t.iat, ErrJWInvalid)
+	}
+
+	if t.opt.Issuer != "" && t.issuer != t.opt.Issuer {
+		return fmt.Errorf("unexpected issuer: %s: %w", t.issuer, ErrJWInvalid)
+	}
+
+	if len(t.opt.Audience) == 0 {
+		return nil
+	}
+
+	for _, want := range t.opt.Audience {
+		for _, aud := range t.audience {
+			if aud == want {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("token is not intended for this audience: %v: %w", t.audience,
+		ErrJWInvalid)
+}
+
 func (t *JW) Token() string {
 	return t.tokenStr
 }
 
 func (t *JW) Generate() error {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &authClaims{
-		Username: t.username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: t.exp.Unix(),
-		},
-	})
-
-	tokenStr, err := token.SignedString([]byte(t.secret))
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("could not generate jwt id: %w", err)
+	}
+	t.id = hex.EncodeToString(id)
+
+	claims := &authClaims{
+		Username:  t.username,
+		Subject:   t.username,
+		Issuer:    t.issuer,
+		ExpiresAt: t.exp.Unix(),
+		IssuedAt:  t.iat.Unix(),
+		NotBefore: t.nbf.Unix(),
+		Id:        t.id,
+
+		Roles:       t.grants.Roles,
+		Permissions: t.grants.Permissions,
+		PermLevel:   t.grants.PermLevel,
+	}
+
+	// a single audience is sent as a string since some consumers don't accept an array
+	switch len(t.audience) {
+	case 0:
+	case 1:
+		claims.Audience = t.audience[0]
+	default:
+		claims.Audience = t.audience
+	}
+
+	token := jwt.NewWithClaims(t.key.method, claims)
+	token.Header["kid"] = t.key.id
+
+	tokenStr, err := t.key.sign(token)
 	if err != nil {
 		return fmt.Errorf("could not sign jwt: %w", err)
 	}
@@ -86,10 +259,56 @@ func (t *JW) Generate() error {
 	return nil
 }
 
+// SetGrants will set the roles and permissions embedded in the jwt when it is generated
+func (t *JW) SetGrants(grants Grants) {
+	t.grants = grants
+}
+
+func (t *JW) Grants() Grants {
+	return t.grants
+}
+
+// HasPermission will check if the jwt grants a permission
+func (t *JW) HasPermission(permission string) bool {
+	for _, perm := range t.grants.Permissions {
+		if perm == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *JW) Username() string {
+	return t.username
+}
+
+// Id is the unique jti claim of the jwt, empty for tokens issued before it was added
+func (t *JW) Id() string {
+	return t.id
+}
+
+func (t *JW) Issuer() string {
+	return t.issuer
+}
+
+func (t *JW) Audience() []string {
+	return t.audience
+}
+
+func (t *JW) IssuedAt() time.Time {
+	return t.iat
+}
+
+func (t *JW) NotBefore() time.Time {
+	return t.nbf
+}
+
 func (t *JW) ExpiresIn() time.Duration {
 	return time.Until(t.exp)
 }
 
+// IsExpired will check if the jwt has expired, allowing for the leeway of the options
 func (t *JW) IsExpired() bool {
-	return t.exp.Unix() < time.Now().Unix()
+	return time.Now().After(t.exp.Add(t.opt.Leeway))
 }
@@ -1,17 +1,136 @@
 package token_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/joshturge-io/auth/pkg/token"
 )
 
 var secret = "secret"
 
 func TestGenerate(t *testing.T) {
-	jw := token.NewJW(secret, "", 15*time.Minute)
+	jw := token.NewJW(token.NewHMACKey(secret), nil, "", 15*time.Minute)
 	if err := jw.Generate(); err != nil {
 		t.Error(err)
 	}
 }
+
+func TestRegisteredClaims(t *testing.T) {
+	key := token.NewHMACKey(secret)
+	opt := &token.Options{Issuer: "auth", Audience: []string{"api", "web"}}
+
+	jw := token.NewJW(key, opt, "user", 15*time.Minute)
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := token.NewJWFromExisting(key, opt, jw.Token())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Username() != "user" || parsed.Issuer() != "auth" || len(parsed.Audience()) != 2 {
+		t.Errorf("unexpected claims: sub: %s iss: %s aud: %v", parsed.Username(),
+			parsed.Issuer(), parsed.Audience())
+	}
+
+	if parsed.Id() == "" || parsed.Id() != jw.Id() {
+		t.Errorf("jti does not match wanted: %s got: %s", jw.Id(), parsed.Id())
+	}
+
+	other := token.NewJW(key, nil, "user", 15*time.Minute)
+	if err = other.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if other.Id() == jw.Id() {
+		t.Error("jti should be unique")
+	}
+
+	for _, wrong := range []*token.Options{
+		{Issuer: "someone_else"},
+		{Audience: []string{"billing"}},
+	} {
+		if _, err = token.NewJWFromExisting(key, wrong, jw.Token()); !errors.Is(err,
+			token.ErrJWInvalid) {
+			t.Errorf("expected ErrJWInvalid for options %+v got: %v", wrong, err)
+		}
+	}
+
+	if _, err = token.NewJWFromExisting(key, &token.Options{Audience: []string{"web"}},
+		jw.Token()); err != nil {
+		t.Errorf("token should be valid for one of its audiences got: %s", err)
+	}
+}
+
+func TestLeeway(t *testing.T) {
+	key := token.NewHMACKey(secret)
+
+	jw := token.NewJW(key, nil, "user", -2*time.Second)
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := token.NewJWFromExisting(key, nil, jw.Token()); !errors.Is(err,
+		token.ErrJWExpired) {
+		t.Errorf("expected ErrJWExpired got: %v", err)
+	}
+
+	if _, err := token.NewJWFromExisting(key, &token.Options{Leeway: time.Minute},
+		jw.Token()); err != nil {
+		t.Errorf("expired token should be accepted within leeway got: %s", err)
+	}
+}
+
+func TestLegacyClaims(t *testing.T) {
+	// tokens issued before the registered claims were added only have username and exp
+	legacy := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"username": "user",
+		"exp":      time.Now().Add(time.Minute).Unix(),
+	})
+
+	tokenStr, err := legacy.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jw, err := token.NewJWFromExisting(token.NewHMACKey(secret), nil, tokenStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if jw.Username() != "user" {
+		t.Errorf("username does not match wanted: user got: %s", jw.Username())
+	}
+}
+
+func TestGrants(t *testing.T) {
+	key := token.NewHMACKey(secret)
+
+	jw := token.NewJW(key, nil, "user", 15*time.Minute)
+	jw.SetGrants(token.Grants{
+		Roles:       []string{"admin"},
+		Permissions: []string{"users:manage"},
+		PermLevel:   100,
+	})
+	if err := jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := token.NewJWFromExisting(key, nil, jw.Token())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grants := parsed.Grants()
+	if len(grants.Roles) != 1 || grants.Roles[0] != "admin" || grants.PermLevel != 100 {
+		t.Errorf("unexpected grants: %+v", grants)
+	}
+
+	if !parsed.HasPermission("users:manage") || parsed.HasPermission("sessions:revoke") {
+		t.Errorf("unexpected permissions: %v", grants.Permissions)
+	}
+}
@@ -0,0 +1,216 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrUnsupportedAlg = errors.New("signing algorithm is not supported")
+	ErrKeyCannotSign  = errors.New("key can only be used for verification")
+)
+
+// Supported signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Verifier finds the key used to verify a parsed token
+type Verifier interface {
+	VerifyKey(token *jwt.Token) (interface{}, error)
+}
+
+// Key signs and verifies jwts with a single signing algorithm. Keys parsed from a public key
+// can only be used for verification
+type Key struct {
+	id      string
+	method  jwt.SigningMethod
+	private interface{}
+	public  interface{}
+}
+
+// newKey will create a key with an id derived from its public half
+func newKey(method jwt.SigningMethod, private, public interface{}) *Key {
+	k := &Key{method: method, private: private, public: public}
+	if jwk := newJWK(method.Alg(), public); jwk != nil {
+		k.id = jwk.thumbprint()
+	}
+
+	return k
+}
+
+// NewHMACKey will create a key that signs and verifies with a shared secret using HS256
+func NewHMACKey(secret string) *Key {
+	// the id is derived with the secret so it can be sent in tokens without revealing it
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("kid"))
+
+	k := newKey(jwt.SigningMethodHS256, []byte(secret), []byte(secret))
+	k.id = base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+
+	return k
+}
+
+// ParsePrivateKey will parse a PEM encoded private key for an asymmetric signing algorithm
+func ParsePrivateKey(alg string, pemBytes []byte) (*Key, error) {
+	switch alg {
+	case AlgRS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rsa private key: %w", err)
+		}
+		return newKey(jwt.SigningMethodRS256, private, &private.PublicKey), nil
+	case AlgES256:
+		private, err := jwt.ParseECPrivateKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ecdsa private key: %w", err)
+		}
+		if private.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ecdsa private key is not on the P-256 curve: %w",
+				ErrUnsupportedAlg)
+		}
+		return newKey(jwt.SigningMethodES256, private, &private.PublicKey), nil
+	case AlgEdDSA:
+		block, _ := pem.Decode(pemBytes)
+		if block == nil {
+			return nil, errors.New("failed to decode ed25519 private key: not PEM encoded")
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ed25519 private key: %w", err)
+		}
+		private, ok := parsed.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is not an ed25519 key got: %T", parsed)
+		}
+		return newKey(SigningMethodEdDSA, private, private.Public()), nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", alg, ErrUnsupportedAlg)
+}
+
+// ParsePublicKey will parse a PEM encoded public key for an asymmetric signing algorithm, the
+// key can only be used for verification
+func ParsePublicKey(alg string, pemBytes []byte) (*Key, error) {
+	switch alg {
+	case AlgRS256:
+		public, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse rsa public key: %w", err)
+		}
+		return newKey(jwt.SigningMethodRS256, nil, public), nil
+	case AlgES256:
+		public, err := jwt.ParseECPublicKeyFromPEM(pemBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ecdsa public key: %w", err)
+		}
+		if public.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("ecdsa public key is not on the P-256 curve: %w",
+				ErrUnsupportedAlg)
+		}
+		return newKey(jwt.SigningMethodES256, nil, public), nil
+	case AlgEdDSA:
+		block, _ := pem.Decode(pemBytes)
+		if block == nil {
+			return nil, errors.New("failed to decode ed25519 public key: not PEM encoded")
+		}
+		parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ed25519 public key: %w", err)
+		}
+		public, ok := parsed.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is not an ed25519 key got: %T", parsed)
+		}
+		return newKey(SigningMethodEdDSA, nil, public), nil
+	}
+
+	return nil, fmt.Errorf("%s: %w", alg, ErrUnsupportedAlg)
+}
+
+// LoadPrivateKey will read and parse a PEM encoded private key file
+func LoadPrivateKey(alg, path string) (*Key, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read private key: %w", err)
+	}
+
+	return ParsePrivateKey(alg, pemBytes)
+}
+
+// LoadPublicKey will read and parse a PEM encoded public key file
+func LoadPublicKey(alg, path string) (*Key, error) {
+	pemBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read public key: %w", err)
+	}
+
+	return ParsePublicKey(alg, pemBytes)
+}
+
+// Id is the key id sent in the kid header of tokens the key signs
+func (k *Key) Id() string {
+	return k.id
+}
+
+// Algorithm the key signs and verifies with
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// CanSign will return true if the key holds a private key or secret
+func (k *Key) CanSign() bool {
+	return k.private != nil
+}
+
+// Public will get a copy of the key that can only verify, returns nil for HMAC keys since
+// their secret can't be shared
+func (k *Key) Public() *Key {
+	if _, ok := k.method.(*jwt.SigningMethodHMAC); ok {
+		return nil
+	}
+
+	return &Key{k.id, k.method, nil, k.public}
+}
+
+// JWK will get the public half of the key as a jwk, returns nil for HMAC keys
+func (k *Key) JWK() *JWK {
+	jwk := newJWK(k.method.Alg(), k.public)
+	if jwk != nil {
+		jwk.Kid = k.id
+	}
+
+	return jwk
+}
+
+// VerifyKey will make sure the token was signed with the keys algorithm
+func (k *Key) VerifyKey(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != k.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v: %w", token.Header["alg"],
+			ErrJWInvalid)
+	}
+
+	return k.public, nil
+}
+
+// sign a jwt with the key
+func (k *Key) sign(token *jwt.Token) (string, error) {
+	if !k.CanSign() {
+		return "", ErrKeyCannotSign
+	}
+
+	return token.SignedString(k.private)
+}
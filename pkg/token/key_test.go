@@ -0,0 +1,129 @@
+package token_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// generatePEM will generate a PEM encoded key pair for an asymmetric signing algorithm
+func generatePEM(t *testing.T, alg string) (private, public []byte) {
+	var (
+		priv, pub interface{}
+		err       error
+	)
+	switch alg {
+	case token.AlgRS256:
+		var key *rsa.PrivateKey
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		priv, pub = key, &key.PublicKey
+	case token.AlgES256:
+		var key *ecdsa.PrivateKey
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		priv, pub = key, &key.PublicKey
+	case token.AlgEdDSA:
+		pub, priv, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// jwt-go only parses PKCS1 and EC private keys
+	privBlock := &pem.Block{Type: "PRIVATE KEY", Bytes: privDER}
+	switch key := priv.(type) {
+	case *rsa.PrivateKey:
+		privBlock = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}
+	case *ecdsa.PrivateKey:
+		ecDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privBlock = &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}
+	}
+
+	return pem.EncodeToMemory(privBlock),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+}
+
+func TestAsymmetricKeys(t *testing.T) {
+	for _, alg := range []string{token.AlgRS256, token.AlgES256, token.AlgEdDSA} {
+		privPEM, pubPEM := generatePEM(t, alg)
+
+		private, err := token.ParsePrivateKey(alg, privPEM)
+		if err != nil {
+			t.Errorf("%s: %s", alg, err)
+			continue
+		}
+
+		jw := token.NewJW(private, nil, "user", 15*time.Minute)
+		if err = jw.Generate(); err != nil {
+			t.Errorf("%s: %s", alg, err)
+			continue
+		}
+
+		public, err := token.ParsePublicKey(alg, pubPEM)
+		if err != nil {
+			t.Errorf("%s: %s", alg, err)
+			continue
+		}
+
+		parsed, err := token.NewJWFromExisting(public, nil, jw.Token())
+		if err != nil {
+			t.Errorf("%s: failed to verify with public key: %s", alg, err)
+			continue
+		}
+
+		if parsed.Username() != "user" {
+			t.Errorf("%s: username does not match wanted: user got: %s", alg,
+				parsed.Username())
+		}
+
+		if err = token.NewJW(public, nil, "user", time.Minute).Generate(); !errors.Is(err,
+			token.ErrKeyCannotSign) {
+			t.Errorf("%s: expected ErrKeyCannotSign got: %v", alg, err)
+		}
+
+		if _, err = token.NewJWFromExisting(token.NewHMACKey(secret), nil,
+			jw.Token()); !errors.Is(err, token.ErrJWInvalid) {
+			t.Errorf("%s: expected ErrJWInvalid when verifying with HS256 got: %v", alg, err)
+		}
+	}
+}
+
+func TestAlgorithmConfusion(t *testing.T) {
+	_, pubPEM := generatePEM(t, token.AlgRS256)
+	public, err := token.ParsePublicKey(token.AlgRS256, pubPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a token signed with the public key as an HMAC secret must not verify
+	jw := token.NewJW(token.NewHMACKey(string(pubPEM)), nil, "admin", 15*time.Minute)
+	if err = jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = token.NewJWFromExisting(public, nil, jw.Token()); !errors.Is(err,
+		token.ErrJWInvalid) {
+		t.Errorf("expected ErrJWInvalid got: %v", err)
+	}
+}
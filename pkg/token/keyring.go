@@ -0,0 +1,72 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var ErrUnknownKey = errors.New("token was signed with an unknown key")
+
+// Keyring holds the active signing key along with previous keys that tokens in flight may
+// still be signed with. Previous keys are only used for verification
+type Keyring struct {
+	active *Key
+	keys   []*Key
+	byId   map[string]*Key
+}
+
+// NewKeyring will create a keyring that signs with active and verifies with active and any
+// previous keys, returns ErrKeyCannotSign if the active key can't sign
+func NewKeyring(active *Key, previous ...*Key) (*Keyring, error) {
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key: %w", ErrKeyCannotSign)
+	}
+
+	kr := &Keyring{active: active, byId: make(map[string]*Key, len(previous)+1)}
+	for _, key := range append([]*Key{active}, previous...) {
+		if _, ok := kr.byId[key.id]; ok {
+			continue
+		}
+		kr.keys = append(kr.keys, key)
+		kr.byId[key.id] = key
+	}
+
+	return kr, nil
+}
+
+// Active is the key new tokens are signed with
+func (kr *Keyring) Active() *Key {
+	return kr.active
+}
+
+// VerifyKey will find the key a token was signed with by its kid header. Tokens issued before
+// key ids were added are verified with the active key
+func (kr *Keyring) VerifyKey(token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"]
+	if !ok {
+		return kr.active.VerifyKey(token)
+	}
+
+	id, _ := kid.(string)
+	key, ok := kr.byId[id]
+	if !ok {
+		return nil, fmt.Errorf("%v: %w", kid, ErrUnknownKey)
+	}
+
+	return key.VerifyKey(token)
+}
+
+// JWKS will get the public half of every key in the keyring that can be shared, HMAC keys
+// are left out
+func (kr *Keyring) JWKS() *JWKS {
+	jwks := &JWKS{Keys: []*JWK{}}
+	for _, key := range kr.keys {
+		if jwk := key.JWK(); jwk != nil {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	return jwks
+}
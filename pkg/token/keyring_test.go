@@ -0,0 +1,83 @@
+package token_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+func TestKeyring(t *testing.T) {
+	oldPEM, _ := generatePEM(t, token.AlgRS256)
+	old, err := token.ParsePrivateKey(token.AlgRS256, oldPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	activePEM, _ := generatePEM(t, token.AlgES256)
+	active, err := token.ParsePrivateKey(token.AlgES256, activePEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kr, err := token.NewKeyring(active, old.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []*token.Key{active, old} {
+		jw := token.NewJW(key, nil, "user", 15*time.Minute)
+		if err = jw.Generate(); err != nil {
+			t.Fatal(err)
+		}
+
+		parsed, _, err := new(jwt.Parser).ParseUnverified(jw.Token(), jwt.MapClaims{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if parsed.Header["kid"] != key.Id() {
+			t.Errorf("kid header does not match wanted: %s got: %v", key.Id(),
+				parsed.Header["kid"])
+		}
+
+		if _, err = token.NewJWFromExisting(kr, nil, jw.Token()); err != nil {
+			t.Errorf("%s: failed to verify with keyring: %s", key.Algorithm(), err)
+		}
+	}
+
+	jwks := kr.JWKS()
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected 2 keys in the key set got: %d", len(jwks.Keys))
+	}
+
+	if jwks.Keys[0].Kid != active.Id() || jwks.Keys[0].Kty != "EC" ||
+		jwks.Keys[0].Crv != "P-256" {
+		t.Errorf("unexpected active jwk: %+v", jwks.Keys[0])
+	}
+
+	if jwks.Keys[1].Kid != old.Id() || jwks.Keys[1].Kty != "RSA" || jwks.Keys[1].E != "AQAB" {
+		t.Errorf("unexpected previous jwk: %+v", jwks.Keys[1])
+	}
+
+	unknownPEM, _ := generatePEM(t, token.AlgES256)
+	unknown, err := token.ParsePrivateKey(token.AlgES256, unknownPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jw := token.NewJW(unknown, nil, "user", 15*time.Minute)
+	if err = jw.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = token.NewJWFromExisting(kr, nil, jw.Token()); !errors.Is(err, token.ErrJWInvalid) {
+		t.Errorf("expected ErrJWInvalid for an unknown key got: %v", err)
+	}
+
+	if _, err = token.NewKeyring(old.Public()); !errors.Is(err, token.ErrKeyCannotSign) {
+		t.Errorf("expected ErrKeyCannotSign for a verify only active key got: %v", err)
+	}
+}
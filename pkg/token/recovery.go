@@ -0,0 +1,51 @@
+package token
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// RecoveryCodeLength is the number of characters in a recovery code, not counting the
+	// separator
+	RecoveryCodeLength = 10
+	// recoveryAlphabet leaves out characters that are easily confused when read back
+	recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// GenerateRecoveryCodes will generate n random single use recovery codes formatted as two
+// groups of characters, for example: "k3m9x-q2w7p"
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	max := big.NewInt(int64(len(recoveryAlphabet)))
+	codes := make([]string, n)
+	for i := range codes {
+		var b strings.Builder
+		for j := 0; j < RecoveryCodeLength; j++ {
+			if j == RecoveryCodeLength/2 {
+				b.WriteByte('-')
+			}
+
+			c, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return nil, fmt.Errorf("could not generate rand int: %w", err)
+			}
+			b.WriteByte(recoveryAlphabet[c.Int64()])
+		}
+		codes[i] = b.String()
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode will remove separators and whitespace from a recovery code and lower
+// its case so it can be compared with a generated one
+func NormalizeRecoveryCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '\t' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+}
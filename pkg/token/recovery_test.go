@@ -0,0 +1,36 @@
+package token_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := token.GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(codes) != 10 {
+		t.Fatalf("expected 10 codes got: %d", len(codes))
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != token.RecoveryCodeLength+1 || code[token.RecoveryCodeLength/2] != '-' {
+			t.Errorf("unexpected recovery code format: %s", code)
+		}
+
+		if seen[code] {
+			t.Errorf("duplicate recovery code: %s", code)
+		}
+		seen[code] = true
+
+		normalized := token.NormalizeRecoveryCode(" " + strings.ToUpper(code) + " ")
+		if normalized != strings.Replace(code, "-", "", 1) {
+			t.Errorf("normalized code does not match wanted: %s got: %s", code, normalized)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrInvalidTOTPSecret = errors.New("totp secret is not valid")
+
+const (
+	// TOTPPeriod is how long each totp code is valid for
+	TOTPPeriod = 30 * time.Second
+	// TOTPDigits is the number of digits in a totp code
+	TOTPDigits = 6
+	// totpSecretLength is the number of random bytes in a totp secret
+	totpSecretLength = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret will generate a random base32 encoded totp secret
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("could not generate rand bytes: %w", err)
+	}
+
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPURI will create an otpauth uri for a secret that authenticator apps can read from a QR
+// code
+func TOTPURI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(TOTPDigits))
+	v.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+
+	label := url.PathEscape(issuer + ":" + account)
+
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// TOTPStep will get the time step a time falls in
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode will generate the totp code of a base32 encoded secret for a time step as described
+// in RFC 6238
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", err, ErrInvalidTOTPSecret)
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", TOTPDigits, code%1000000), nil
+}
+
+// ValidateTOTP will check a code against the time steps within skew steps of t, returns the
+// time step the code matched so it can't be replayed
+func ValidateTOTP(secret, code string, t time.Time, skew int) (int64, bool, error) {
+	now := TOTPStep(t)
+	for i := -skew; i <= skew; i++ {
+		want, err := TOTPCode(secret, now+int64(i))
+		if err != nil {
+			return 0, false, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return now + int64(i), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
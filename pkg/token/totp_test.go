@@ -0,0 +1,63 @@
+package token_test
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/joshturge-io/auth/pkg/token"
+)
+
+// rfc6238Secret is the SHA1 test secret from RFC 6238
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// the RFC 6238 test vectors truncated to 6 digits
+	for unix, want := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		code, err := token.TOTPCode(rfc6238Secret, token.TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if code != want {
+			t.Errorf("%d: code does not match wanted: %s got: %s", unix, want, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := token.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	previous, err := token.TOTPCode(secret, token.TOTPStep(now)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok, err := token.ValidateTOTP(secret, previous, now, 1)
+	if err != nil || !ok {
+		t.Errorf("code from the previous step should be valid within skew got: %v", err)
+	}
+
+	if step != token.TOTPStep(now)-1 {
+		t.Errorf("matched step does not match wanted: %d got: %d", token.TOTPStep(now)-1, step)
+	}
+
+	if _, ok, _ = token.ValidateTOTP(secret, previous, now, 0); ok {
+		t.Error("code from the previous step should not be valid without skew")
+	}
+
+	if _, _, err = token.ValidateTOTP("not base32!", "123456", now, 1); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+}